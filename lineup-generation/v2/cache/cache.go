@@ -0,0 +1,157 @@
+package cache
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+	d "v2/data"
+	u "v2/utils"
+)
+
+// Struct for an in-memory cache of optimizer responses with a TTL and a maximum number of entries (evicting the least recently used)
+type Cache struct {
+	mu          sync.Mutex
+	entries     map[string]*list.Element
+	order       *list.List
+	max_entries int
+	ttl         time.Duration
+}
+
+// Struct for a single cached response
+type entry struct {
+	key      string
+	response u.Response
+	expires  time.Time
+}
+
+// Function to create a new cache
+func New(max_entries int, ttl time.Duration) *Cache {
+	return &Cache{
+		entries:     make(map[string]*list.Element),
+		order:       list.New(),
+		max_entries: max_entries,
+		ttl:         ttl,
+	}
+}
+
+// Function to get a cached response if there is one that hasn't expired
+func (c *Cache) Get(key string) (u.Response, bool) {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		return u.Response{}, false
+	}
+
+	cached := element.Value.(*entry)
+	if time.Now().After(cached.expires) {
+		c.remove(element)
+		return u.Response{}, false
+	}
+
+	c.order.MoveToFront(element)
+	return cached.response, true
+}
+
+// Function to store a response, evicting the least recently used entries if the cache is full
+func (c *Cache) Set(key string, response u.Response) {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.entries[key]; ok {
+		cached := element.Value.(*entry)
+		cached.response = response
+		cached.expires = time.Now().Add(c.ttl)
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&entry{key: key, response: response, expires: time.Now().Add(c.ttl)})
+	for c.order.Len() > c.max_entries {
+		c.remove(c.order.Back())
+	}
+}
+
+// Function to get the number of entries in the cache, including expired ones that haven't been cleaned up yet
+func (c *Cache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// Function to remove an entry. The caller must hold the lock
+func (c *Cache) remove(element *list.Element) {
+	c.order.Remove(element)
+	delete(c.entries, element.Value.(*entry).key)
+}
+
+// Struct for everything that determines the outcome of an optimization run
+type keyFields struct {
+	LeagueId   int                 `json:"league_id"`
+	TeamName   string              `json:"team_name"`
+	Year       int                 `json:"year"`
+	Week       string              `json:"week"`
+	StartDay   *int                `json:"start_day"`
+	Acquired   int                 `json:"acquisitions_used"`
+	Limit      *d.AcquisitionLimit `json:"acquisition_limit"`
+	Template   *d.RosterTemplate   `json:"roster_template"`
+	Chances    d.PlayProbabilities `json:"play_probabilities"`
+	Projection string              `json:"projection"`
+	Slotting   string              `json:"slotting"`
+	Weights    *d.RecencyWeighted  `json:"recency_weights"`
+	Categories *d.CategoryLeague   `json:"categories"`
+	Opponent   string              `json:"opponent_team_name"`
+	Points     [2]float64          `json:"points_so_far"`
+	Threshold  float64             `json:"threshold"`
+	Optimizer  u.OptimizerConfig   `json:"optimizer"`
+	Seed       *int64              `json:"seed"`
+	Roster     []d.Player          `json:"roster"`
+	FreeAgents []d.Player          `json:"free_agents"`
+}
+
+// Function to build the cache key for a request from its league, team, week and threshold along with a hash of the fetched roster and free agents.
+// Requests without an explicit seed share an entry, since any seed is as good as another
+func Key(req u.ReqBody, config u.OptimizerConfig, roster_map map[string]d.Player, free_agents []d.Player) string {
+
+	// Put the roster in a fixed order since map iteration order is random
+	roster := make([]d.Player, 0, len(roster_map))
+	for _, player := range roster_map {
+		roster = append(roster, player)
+	}
+	sort.Slice(roster, func(i, j int) bool {
+		return roster[i].Name < roster[j].Name
+	})
+
+	json_data, _ := json.Marshal(keyFields{
+		LeagueId:   req.LeagueId,
+		TeamName:   req.TeamName,
+		Year:       req.Year,
+		Week:       req.Week,
+		StartDay:   req.StartDay,
+		Acquired:   req.AcquisitionsUsed,
+		Limit:      req.AcquisitionLimit,
+		Template:   req.RosterTemplate,
+		Chances:    req.PlayProbabilities,
+		Projection: req.Projection,
+		Slotting:   req.Slotting,
+		Weights:    req.RecencyWeights,
+		Categories: req.Categories,
+		Opponent:   req.OpponentTeamName,
+		Points:     [2]float64{req.PointsSoFar, req.OpponentPointsSoFar},
+		Threshold:  req.Threshold,
+		Optimizer:  config,
+		Seed:       req.Seed,
+		Roster:     roster,
+		FreeAgents: free_agents,
+	})
+
+	hash := sha256.Sum256(json_data)
+	return hex.EncodeToString(hash[:])
+}
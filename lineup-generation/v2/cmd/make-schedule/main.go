@@ -0,0 +1,54 @@
+// Command make-schedule builds the schedule file the optimizer reads from a local copy of the NBA's scheduleLeagueV2 feed
+// and a league calendar, e.g.
+//
+//	curl -o schedule_raw.json https://cdn.nba.com/static/json/staticData/scheduleLeagueV2.json
+//	go run ./cmd/make-schedule -nba schedule_raw.json -calendar static/calendar24-25.json -out static/schedule24-25.json
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"v2/schedule"
+)
+
+func main() {
+
+	nba_path := flag.String("nba", "", "path to the NBA's scheduleLeagueV2 JSON")
+	calendar_path := flag.String("calendar", "", "path to the league calendar JSON")
+	out_path := flag.String("out", "", "path to write the schedule to")
+	flag.Parse()
+
+	if *nba_path == "" || *calendar_path == "" || *out_path == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err := run(*nba_path, *calendar_path, *out_path); err != nil {
+		fmt.Fprintln(os.Stderr, "make-schedule:", err)
+		os.Exit(1)
+	}
+}
+
+func run(nba_path string, calendar_path string, out_path string) error {
+
+	nba, err := schedule.LoadNBASchedule(nba_path)
+	if err != nil {
+		return err
+	}
+	calendar, err := schedule.LoadCalendar(calendar_path)
+	if err != nil {
+		return err
+	}
+
+	season, err := schedule.Build(nba, calendar)
+	if err != nil {
+		return err
+	}
+	if err := schedule.Write(out_path, season); err != nil {
+		return err
+	}
+
+	fmt.Println("Wrote", len(season.Schedule), "matchups to", out_path)
+	return nil
+}
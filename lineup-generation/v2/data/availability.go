@@ -0,0 +1,76 @@
+package data
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Injury designations that a player can be listed with
+const (
+	StatusOut          = "OUT"
+	StatusDoubtful     = "DOUBTFUL"
+	StatusQuestionable = "QUESTIONABLE"
+	StatusProbable     = "PROBABLE"
+	StatusDayToDay     = "DTD"
+)
+
+// Map from an injury designation to the chance that a player listed with it plays
+type PlayProbabilities map[string]float64
+
+// Function to get the chances used when a request doesn't set its own
+func DefaultPlayProbabilities() PlayProbabilities {
+	return PlayProbabilities{
+		StatusOut:          0.0,
+		StatusDoubtful:     0.25,
+		StatusQuestionable: 0.5,
+		StatusProbable:     0.85,
+		StatusDayToDay:     0.6,
+	}
+}
+
+// Separators that designations are written with, which don't change what they mean
+var status_separators = strings.NewReplacer("_", "", "-", "", " ", "")
+
+// Function to put a designation in the form used as a key, so that ESPN's DAY_TO_DAY and a hand-written "day-to-day" both mean DTD
+func NormalizeStatus(status string) string {
+	status = strings.ToUpper(strings.TrimSpace(status))
+	switch status_separators.Replace(status) {
+	case "DAYTODAY", "DTD":
+		return StatusDayToDay
+	}
+	return status
+}
+
+// Function to check that every designation is known and every chance is between 0 and 1
+func (p PlayProbabilities) Validate() error {
+	defaults := DefaultPlayProbabilities()
+	for status, probability := range p {
+		if _, ok := defaults[NormalizeStatus(status)]; !ok {
+			return fmt.Errorf("%w: unknown designation %q", ErrInvalidPlayProbabilities, status)
+		}
+		if probability < 0 || probability > 1 {
+			return fmt.Errorf("%w: %q has chance %v", ErrInvalidPlayProbabilities, status, probability)
+		}
+	}
+	return nil
+}
+
+// Function to get the defaults with any chances set in p taking their place
+func (p PlayProbabilities) Merge() PlayProbabilities {
+	merged := DefaultPlayProbabilities()
+	for status, probability := range p {
+		merged[NormalizeStatus(status)] = probability
+	}
+	return merged
+}
+
+// Function to get the chance that a player plays. Players without a known designation play unless they are injured
+func (p PlayProbabilities) Of(player Player) float64 {
+	if probability, ok := p[NormalizeStatus(player.InjuryStatus)]; ok {
+		return probability
+	}
+	if player.Injured {
+		return 0
+	}
+	return 1
+}
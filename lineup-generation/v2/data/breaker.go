@@ -0,0 +1,81 @@
+package data
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+var ErrCircuitOpen = fmt.Errorf("%w: circuit breaker is open", ErrBackendUnavailable)
+
+// Struct for a circuit breaker that fails fast once the backend has failed too many times in a row.
+// After the cooldown a single trial call is let through, and its outcome decides whether the circuit closes again
+type CircuitBreaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	failures  int
+	opened_at time.Time
+	trial     bool
+}
+
+// Function to create a new circuit breaker. A threshold of 0 disables it
+func NewCircuitBreaker(threshold int, cooldown time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{threshold: threshold, cooldown: cooldown}
+}
+
+// Function to check whether a call may go through
+func (b *CircuitBreaker) Allow() error {
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.threshold <= 0 || b.failures < b.threshold {
+		return nil
+	}
+
+	// Open: fail fast until the cooldown has passed, then let a single trial call through
+	if time.Since(b.opened_at) < b.cooldown || b.trial {
+		return ErrCircuitOpen
+	}
+	b.trial = true
+	return nil
+}
+
+// Function to record a successful call, closing the circuit
+func (b *CircuitBreaker) Success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures = 0
+	b.trial = false
+}
+
+// Function to record a failed call, opening the circuit once the threshold is reached
+func (b *CircuitBreaker) Failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	b.trial = false
+	if b.threshold > 0 && b.failures >= b.threshold {
+		b.opened_at = time.Now()
+	}
+}
+
+// Function to give up the trial call without recording an outcome, for calls that failed on the caller's side and say nothing about
+// the backend
+func (b *CircuitBreaker) Release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.trial = false
+}
+
+// Function to check whether the circuit is currently open
+func (b *CircuitBreaker) IsOpen() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.threshold > 0 && b.failures >= b.threshold && time.Since(b.opened_at) < b.cooldown
+}
@@ -0,0 +1,236 @@
+package data
+
+import (
+	"fmt"
+	"math"
+)
+
+// Categories that a head-to-head category league can count
+const (
+	CatPoints    = "PTS"
+	CatRebounds  = "REB"
+	CatAssists   = "AST"
+	CatSteals    = "STL"
+	CatBlocks    = "BLK"
+	CatThrees    = "3PM"
+	CatFGPct     = "FG%"
+	CatFTPct     = "FT%"
+	CatTurnovers = "TO"
+)
+
+// Ways a category league can be scored
+const (
+	ScoringScaled = "scaled" // Projected totals measured from zero, with each category scaled by the standard deviation of a game across the pool of rostered players and free agents
+	ScoringWins   = "wins"   // Number of categories won against the opponent's projected totals
+)
+
+// Function to get the categories of a standard 9-cat league
+func NineCat() []string {
+	return []string{CatPoints, CatRebounds, CatAssists, CatSteals, CatBlocks, CatThrees, CatFGPct, CatFTPct, CatTurnovers}
+}
+
+// Function to get the categories of a standard 8-cat league, which is 9-cat without turnovers
+func EightCat() []string {
+	return NineCat()[:8]
+}
+
+// Struct for a player's per-game averages in each category, or a team's totals over a stretch of games. Percentages are kept as
+// makes and attempts so that they can be added up
+type CategoryStats struct {
+	PTS float64 `json:"pts"`
+	REB float64 `json:"reb"`
+	AST float64 `json:"ast"`
+	STL float64 `json:"stl"`
+	BLK float64 `json:"blk"`
+	TPM float64 `json:"3pm"`
+	FGM float64 `json:"fgm"`
+	FGA float64 `json:"fga"`
+	FTM float64 `json:"ftm"`
+	FTA float64 `json:"fta"`
+	TO  float64 `json:"to"`
+}
+
+// Function to add weight games' worth of other to the stats
+func (s *CategoryStats) Add(other CategoryStats, weight float64) {
+	s.PTS += weight * other.PTS
+	s.REB += weight * other.REB
+	s.AST += weight * other.AST
+	s.STL += weight * other.STL
+	s.BLK += weight * other.BLK
+	s.TPM += weight * other.TPM
+	s.FGM += weight * other.FGM
+	s.FGA += weight * other.FGA
+	s.FTM += weight * other.FTM
+	s.FTA += weight * other.FTA
+	s.TO += weight * other.TO
+}
+
+// Function to get the value of a category, working out percentages from makes and attempts
+func (s CategoryStats) Value(category string) float64 {
+	switch category {
+	case CatPoints:
+		return s.PTS
+	case CatRebounds:
+		return s.REB
+	case CatAssists:
+		return s.AST
+	case CatSteals:
+		return s.STL
+	case CatBlocks:
+		return s.BLK
+	case CatThrees:
+		return s.TPM
+	case CatFGPct:
+		return ratio(s.FGM, s.FGA)
+	case CatFTPct:
+		return ratio(s.FTM, s.FTA)
+	case CatTurnovers:
+		return s.TO
+	}
+	return 0
+}
+
+// Function to divide makes by attempts, treating no attempts as 0
+func ratio(makes float64, attempts float64) float64 {
+	if attempts == 0 {
+		return 0
+	}
+	return makes / attempts
+}
+
+// Function to check if fewer is better in a category
+func LowerIsBetter(category string) bool {
+	return category == CatTurnovers
+}
+
+// Struct for how a category league is set up. Opponent is the other team's projected totals for the matchup, which scoring by
+// wins needs
+type CategoryLeague struct {
+	Categories []string       `json:"categories"`
+	Scoring    string         `json:"scoring"`
+	Opponent   *CategoryStats `json:"opponent"`
+}
+
+// Function to fill in the defaults for anything the league leaves out: 9-cat, scored on the scaled totals
+func (c CategoryLeague) WithDefaults() CategoryLeague {
+	if len(c.Categories) == 0 {
+		c.Categories = NineCat()
+	}
+	if c.Scoring == "" {
+		c.Scoring = ScoringScaled
+	}
+	return c
+}
+
+// Function to check that the league makes sense
+func (c CategoryLeague) Validate() error {
+
+	// Every category is one of the nine
+	known := make(map[string]bool)
+	for _, category := range NineCat() {
+		known[category] = true
+	}
+
+	seen := make(map[string]bool)
+	for _, category := range c.Categories {
+		if !known[category] {
+			return fmt.Errorf("%w: unknown category %q", ErrInvalidCategories, category)
+		}
+		if seen[category] {
+			return fmt.Errorf("%w: category %s appears twice", ErrInvalidCategories, category)
+		}
+		seen[category] = true
+	}
+
+	switch c.Scoring {
+	case ScoringScaled:
+	case ScoringWins:
+		if c.Opponent == nil {
+			return fmt.Errorf("%w: scoring by wins needs the opponent's projected totals", ErrInvalidCategories)
+		}
+	default:
+		return fmt.Errorf("%w: unknown scoring %q", ErrInvalidCategories, c.Scoring)
+	}
+	return nil
+}
+
+// Struct for the scale of each category across a pool of players, used to put categories on the same footing
+type CategoryScales struct {
+	Categories []string
+	FGPct      float64
+	FTPct      float64
+	StdDev     map[string]float64
+}
+
+// Function to measure each category across a pool of players. Percentages are measured by how many makes a player adds over a
+// player shooting the pool's percentage on the same attempts, so that high volume counts for more
+func NewCategoryScales(categories []string, pool []Player) CategoryScales {
+
+	scales := CategoryScales{Categories: categories, StdDev: make(map[string]float64)}
+
+	var total CategoryStats
+	for _, player := range pool {
+		total.Add(player.Stats, 1)
+	}
+	scales.FGPct, scales.FTPct = ratio(total.FGM, total.FGA), ratio(total.FTM, total.FTA)
+
+	if len(pool) == 0 {
+		return scales
+	}
+	for _, category := range categories {
+		mean, square := 0.0, 0.0
+		for _, player := range pool {
+			value := scales.impact(player.Stats, category)
+			mean += value
+			square += value * value
+		}
+		mean /= float64(len(pool))
+		scales.StdDev[category] = math.Sqrt(math.Max(square/float64(len(pool))-mean*mean, 0))
+	}
+	return scales
+}
+
+// Function to get what a player's stats are worth in a category before scaling
+func (s CategoryScales) impact(stats CategoryStats, category string) float64 {
+	switch category {
+	case CatFGPct:
+		return stats.FGM - s.FGPct*stats.FGA
+	case CatFTPct:
+		return stats.FTM - s.FTPct*stats.FTA
+	}
+	return stats.Value(category)
+}
+
+// Function to get the value of a team's totals across the categories, with each category scaled by the standard deviation of a single
+// game across the pool. This isn't a z-score, since no mean is taken off: counting categories are measured from zero, which is what a player who sits adds, since in head-to-head they
+// come from volume: every game played adds to them and only categories where fewer is better count against it. Percentages are
+// measured by makes over the pool's percentage on the same attempts, so more games only help them with above average shooting
+func (s CategoryScales) Value(totals CategoryStats) float64 {
+	total := 0.0
+	for _, category := range s.Categories {
+		if s.StdDev[category] == 0 {
+			continue
+		}
+		value := s.impact(totals, category) / s.StdDev[category]
+		if LowerIsBetter(category) {
+			value = -value
+		}
+		total += value
+	}
+	return total
+}
+
+// Function to count the categories won against an opponent, with a tie counting as half
+func CategoriesWon(categories []string, team CategoryStats, opponent CategoryStats) float64 {
+	won := 0.0
+	for _, category := range categories {
+		ours, theirs := team.Value(category), opponent.Value(category)
+		switch {
+		case ours == theirs:
+			won += 0.5
+		case (ours > theirs) != LowerIsBetter(category):
+			won++
+		}
+	}
+	return won
+}
@@ -0,0 +1,242 @@
+package data
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// Default location of the cv-backend service, overridable with CV_BACKEND_URL
+const DefaultBackendURL = "https://cv-backend-443549036710.us-central1.run.app"
+
+// Paths of the backend endpoints relative to the base URL
+const (
+	RosterPath     = "/data/get_roster_data"
+	FreeAgentsPath = "/data/get_freeagent_data"
+)
+
+// Struct for the settings of the backend client
+type ClientConfig struct {
+	BaseURL          string
+	Timeout          time.Duration // Per attempt, not for the call as a whole
+	MaxRetries       int
+	BaseBackoff      time.Duration
+	MaxBackoff       time.Duration
+	BreakerThreshold int
+	BreakerCooldown  time.Duration
+}
+
+// Function to get the default client settings, taking the base URL from the environment if it is set
+func DefaultClientConfig() ClientConfig {
+
+	base_url := os.Getenv("CV_BACKEND_URL")
+	if base_url == "" {
+		base_url = DefaultBackendURL
+	}
+
+	return ClientConfig{
+		BaseURL:          base_url,
+		Timeout:          20 * time.Second,
+		MaxRetries:       3,
+		BaseBackoff:      250 * time.Millisecond,
+		MaxBackoff:       4 * time.Second,
+		BreakerThreshold: 5,
+		BreakerCooldown:  30 * time.Second,
+	}
+}
+
+// Struct for a client of the cv-backend service with per-call timeouts, retries with jittered exponential backoff and a circuit breaker
+type BackendClient struct {
+	Config  ClientConfig
+	http    *http.Client
+	breaker *CircuitBreaker
+	rng_mu  sync.Mutex
+	rng     *rand.Rand
+}
+
+// Client shared by everything that doesn't bring its own
+var DefaultClient = NewBackendClient(DefaultClientConfig())
+
+// Function to create a new backend client
+func NewBackendClient(config ClientConfig) *BackendClient {
+
+	// Keep connections to the backend alive between requests
+	transport := &http.Transport{
+		Proxy:               http.ProxyFromEnvironment,
+		MaxIdleConns:        16,
+		MaxIdleConnsPerHost: 16,
+		IdleConnTimeout:     90 * time.Second,
+	}
+
+	return &BackendClient{
+		Config:  config,
+		http:    &http.Client{Transport: transport},
+		breaker: NewCircuitBreaker(config.BreakerThreshold, config.BreakerCooldown),
+		rng:     rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// Function to fetch the roster and the free agents concurrently
+func (c *BackendClient) FetchData(ctx context.Context, league LeagueInfo, fa_count int) (map[string]Player, []Player, error) {
+
+	meta := ReqMeta{LeagueInfo: league, FaCount: fa_count}
+	paths := []string{RosterPath, FreeAgentsPath}
+
+	// Launch a goroutine for each endpoint
+	responses := make([]PlayersResponse, len(paths))
+	var wg sync.WaitGroup
+	for i, path := range paths {
+		wg.Add(1)
+		go func(index int, path string) {
+			defer wg.Done()
+			players, err := c.PostPlayers(ctx, path, meta)
+			responses[index] = PlayersResponse{Index: index, Players: players, Err: err}
+		}(i, path)
+	}
+	wg.Wait()
+
+	// Keep the roster's error over the free agents' since it is more specific
+	for _, response := range responses {
+		if response.Err != nil {
+			return nil, nil, response.Err
+		}
+	}
+
+	return PlayersToMap(responses[0].Players), responses[1].Players, nil
+}
+
+// Function to fetch only the roster for a team, for teams whose free agents are never looked at
+func (c *BackendClient) FetchRoster(ctx context.Context, league LeagueInfo) (map[string]Player, error) {
+	players, err := c.PostPlayers(ctx, RosterPath, ReqMeta{LeagueInfo: league})
+	if err != nil {
+		return nil, err
+	}
+	return PlayersToMap(players), nil
+}
+
+// What a single attempt at a backend call says about the backend, which decides whether the circuit breaker hears about it and whether
+// the call is retried
+type outcome int
+
+const (
+	outcomeHealthy   outcome = iota // The backend answered, even if the answer was an error on the caller's side
+	outcomeTransient                // The backend failed in a way that is worth retrying
+	outcomeBroken                   // The backend answered with something that couldn't be read, which retrying won't fix
+	outcomeAborted                  // The call failed on the caller's side and says nothing about the backend
+)
+
+// Function to post the request metadata to a backend endpoint and decode the players it returns, retrying transient failures
+func (c *BackendClient) PostPlayers(ctx context.Context, path string, meta ReqMeta) ([]Player, error) {
+
+	json_meta, err := json.Marshal(meta)
+	if err != nil {
+		return nil, fmt.Errorf("encoding request: %w", err)
+	}
+
+	var last_err error
+	for attempt := 0; attempt <= c.Config.MaxRetries; attempt++ {
+
+		// Back off before retrying, giving up if the caller goes away in the meantime
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(c.Backoff(attempt)):
+			}
+		}
+
+		if err := c.breaker.Allow(); err != nil {
+			return nil, err
+		}
+
+		// Only failures of the backend count against it. Bad credentials and missing leagues are the caller's problem
+		players, result, err := c.post(ctx, path, json_meta)
+		switch result {
+		case outcomeHealthy:
+			c.breaker.Success()
+		case outcomeTransient, outcomeBroken:
+			c.breaker.Failure()
+		case outcomeAborted:
+			c.breaker.Release()
+		}
+		if result != outcomeTransient {
+			return players, err
+		}
+		last_err = err
+	}
+
+	return nil, last_err
+}
+
+// Function to make a single attempt at a backend call, reporting what it says about the backend
+func (c *BackendClient) post(ctx context.Context, path string, json_meta []byte) ([]Player, outcome, error) {
+
+	// Bound each attempt on its own so that one hung connection doesn't eat the whole retry budget
+	attempt_ctx := ctx
+	if c.Config.Timeout > 0 {
+		var cancel context.CancelFunc
+		attempt_ctx, cancel = context.WithTimeout(ctx, c.Config.Timeout)
+		defer cancel()
+	}
+
+	request, err := http.NewRequestWithContext(attempt_ctx, http.MethodPost, c.Config.BaseURL+path, bytes.NewReader(json_meta))
+	if err != nil {
+		return nil, outcomeAborted, fmt.Errorf("creating request: %w", err)
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := c.http.Do(request)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, outcomeAborted, ctx.Err()
+		}
+		return nil, outcomeTransient, fmt.Errorf("%w: %v", ErrBackendUnavailable, err)
+	}
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, outcomeAborted, ctx.Err()
+		}
+		return nil, outcomeTransient, fmt.Errorf("%w: reading response: %v", ErrBackendUnavailable, err)
+	}
+
+	if response.StatusCode != http.StatusOK {
+		err := fmt.Errorf("%w (status %d)", StatusError(response.StatusCode, body), response.StatusCode)
+		if response.StatusCode >= 500 || response.StatusCode == http.StatusTooManyRequests {
+			return nil, outcomeTransient, err
+		}
+		return nil, outcomeHealthy, err
+	}
+
+	var players []Player
+	if err := json.Unmarshal(body, &players); err != nil {
+		return nil, outcomeBroken, fmt.Errorf("%w: decoding player list: %v", ErrBackendUnavailable, err)
+	}
+
+	return players, outcomeHealthy, nil
+}
+
+// Function to get how long to wait before the given retry: exponential in the attempt number, capped, with full jitter
+func (c *BackendClient) Backoff(attempt int) time.Duration {
+
+	backoff := c.Config.BaseBackoff << (attempt - 1)
+	if backoff > c.Config.MaxBackoff || backoff <= 0 {
+		backoff = c.Config.MaxBackoff
+	}
+	if backoff <= 0 {
+		return 0
+	}
+
+	c.rng_mu.Lock()
+	defer c.rng_mu.Unlock()
+	return time.Duration(c.rng.Int63n(int64(backoff) + 1))
+}
@@ -0,0 +1,48 @@
+package data
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// Errors returned when players can't be fetched, so that callers can tell what went wrong
+var (
+	ErrBackendUnavailable = errors.New("player data backend is unavailable")
+	ErrLeagueNotFound     = errors.New("league not found")
+	ErrBadCredentials     = errors.New("invalid or missing ESPN credentials")
+	ErrTeamNotFound       = errors.New("team not found in league")
+)
+
+// Errors returned when a request doesn't line up with the schedule or the state of the matchup
+var (
+	ErrSeasonNotFound  = errors.New("no schedule for season")
+	ErrWeekNotFound    = errors.New("no such week in schedule")
+	ErrDateNotInSeason = errors.New("date is not in any week of the season")
+	ErrInvalidDate     = errors.New("invalid date, expected YYYY-MM-DD")
+	ErrInvalidStartDay = errors.New("start day is outside the week")
+	ErrInvalidAcquisitions = errors.New("acquisitions used can't be negative")
+	ErrInvalidAcquisitionLimit = errors.New("invalid acquisition limit")
+	ErrInvalidRosterTemplate = errors.New("invalid roster template")
+	ErrInvalidPlayProbabilities = errors.New("invalid play probabilities")
+	ErrInvalidProjection = errors.New("invalid projection")
+	ErrInvalidCategories = errors.New("invalid category league")
+	ErrInvalidSlotting = errors.New("invalid slotting")
+)
+
+// Function to map a non-200 status code from the backend to one of the errors above
+func StatusError(status_code int, body []byte) error {
+
+	switch {
+	case status_code == http.StatusUnauthorized || status_code == http.StatusForbidden:
+		return ErrBadCredentials
+	case status_code == http.StatusNotFound:
+		// The backend uses 404 for both missing leagues and missing teams
+		if strings.Contains(strings.ToLower(string(body)), "team") {
+			return ErrTeamNotFound
+		}
+		return ErrLeagueNotFound
+	default:
+		return ErrBackendUnavailable
+	}
+}
@@ -0,0 +1,112 @@
+package data
+
+import (
+	"fmt"
+	"math"
+)
+
+// Periods that a league's acquisition limit can apply to
+const (
+	PeriodMatchup   = "matchup"
+	PeriodWeek      = "week"
+	PeriodUnlimited = "unlimited"
+)
+
+// Struct for a league's limit on acquisitions. Limit applies to each matchup, to each 7-day week of a matchup or not at all,
+// and DailyCap (0 for none) limits the acquisitions on any one day. In hard mode a lineup that breaks the limit is never
+// suggested, otherwise it is only penalized
+type AcquisitionLimit struct {
+	Period   string `json:"period"`
+	Limit    int    `json:"limit"`
+	DailyCap int    `json:"daily_cap"`
+	Hard     bool   `json:"hard"`
+}
+
+// Function to get the limit used when a league doesn't set one: one acquisition per day of the matchup, penalized rather than enforced
+func DefaultAcquisitionLimit(game_span int) AcquisitionLimit {
+	return AcquisitionLimit{Period: PeriodMatchup, Limit: game_span + 1}
+}
+
+// Function to check that the limit makes sense
+func (a AcquisitionLimit) Validate() error {
+
+	switch a.Period {
+	case PeriodMatchup, PeriodWeek, PeriodUnlimited:
+	default:
+		return fmt.Errorf("%w: unknown period %q", ErrInvalidAcquisitionLimit, a.Period)
+	}
+	if a.Limit < 0 || a.DailyCap < 0 {
+		return fmt.Errorf("%w: limit and daily cap can't be negative", ErrInvalidAcquisitionLimit)
+	}
+	return nil
+}
+
+// Function to count how many acquisitions go over the limit. daily[i] is the number of acquisitions on day start_day + i and
+// used is the number made before start_day, which for weekly limits are counted against the week start_day falls in
+func (a AcquisitionLimit) Excess(start_day int, daily []int, used int) int {
+
+	excess := 0
+
+	switch a.Period {
+	case PeriodMatchup:
+		total := 0
+		for _, count := range daily {
+			total += count
+		}
+		excess += max(total-max(a.Limit-used, 0), 0)
+	case PeriodWeek:
+		windows := make(map[int]int)
+		for i, count := range daily {
+			windows[(start_day+i)/7] += count
+		}
+		for window, count := range windows {
+			excess += max(count-a.allowedInWindow(window, start_day, used), 0)
+		}
+	}
+
+	if a.DailyCap > 0 {
+		for _, count := range daily {
+			excess += max(count-a.DailyCap, 0)
+		}
+	}
+
+	return excess
+}
+
+// Function to get how many more acquisitions can be made on day start_day + index, assuming daily holds every acquisition made so far
+func (a AcquisitionLimit) Allowance(start_day int, daily []int, used int, index int) int {
+
+	allowance := math.MaxInt
+
+	switch a.Period {
+	case PeriodMatchup:
+		total := 0
+		for _, count := range daily {
+			total += count
+		}
+		allowance = max(a.Limit-used, 0) - total
+	case PeriodWeek:
+		window := (start_day + index) / 7
+		total := 0
+		for i, count := range daily {
+			if (start_day+i)/7 == window {
+				total += count
+			}
+		}
+		allowance = a.allowedInWindow(window, start_day, used) - total
+	}
+
+	if a.DailyCap > 0 {
+		allowance = min(allowance, a.DailyCap-daily[index])
+	}
+
+	return max(allowance, 0)
+}
+
+// Function to get how many acquisitions are left in a 7-day window of the matchup once the ones already made are taken off
+func (a AcquisitionLimit) allowedInWindow(window int, start_day int, used int) int {
+	if window == start_day/7 {
+		return max(a.Limit-used, 0)
+	}
+	return a.Limit
+}
@@ -5,9 +5,16 @@ package data
 type Player struct {
 	Name           string   `json:"name"`
 	AvgPoints      float64  `json:"avg_points"`
+	AvgLast7       float64  `json:"avg_points_last_7"`
+	AvgLast15      float64  `json:"avg_points_last_15"`
+	AvgLast30      float64  `json:"avg_points_last_30"`
 	Team           string   `json:"team"`
 	ValidPositions []string `json:"valid_positions"`
 	Injured        bool     `json:"injured"`
+	InjuryStatus   string   `json:"injury_status"`
+	ReturnDate     string   `json:"return_date"`
+	OnIR           bool     `json:"on_ir"`
+	Stats          CategoryStats `json:"stats"`
 }
 
 // Functions that return the player's fields
@@ -0,0 +1,98 @@
+package data
+
+import (
+	"fmt"
+	"time"
+)
+
+// Ways a request can have its players projected
+const (
+	ProjectionSeason = "season"
+	ProjectionRecent = "recent"
+	ProjectionFile   = "file"
+)
+
+// Interface for anything that can project the fantasy points a player will score on a date if he plays
+type Projector interface {
+	Project(player Player, date time.Time) float64
+}
+
+// Projector that uses the season average for every game
+type SeasonAverage struct{}
+
+func (SeasonAverage) Project(player Player, date time.Time) float64 {
+	return player.AvgPoints
+}
+
+// Projector that blends the season average with the averages over the last 7, 15 and 30 days. Windows a player has no average for
+// are left out and the rest of the weights are scaled up to make up for them
+type RecencyWeighted struct {
+	Season float64 `json:"season"`
+	Last30 float64 `json:"last_30"`
+	Last15 float64 `json:"last_15"`
+	Last7  float64 `json:"last_7"`
+}
+
+// Function to get the blend used for recent form, which leans on the last two weeks without ignoring the rest of the season
+func DefaultRecencyWeighted() RecencyWeighted {
+	return RecencyWeighted{Season: 0.2, Last30: 0.2, Last15: 0.25, Last7: 0.35}
+}
+
+// Function to check that the weights make sense
+func (r RecencyWeighted) Validate() error {
+	if r.Season < 0 || r.Last30 < 0 || r.Last15 < 0 || r.Last7 < 0 {
+		return fmt.Errorf("%w: recency weights can't be negative", ErrInvalidProjection)
+	}
+	if r.Season+r.Last30+r.Last15+r.Last7 == 0 {
+		return fmt.Errorf("%w: recency weights can't all be zero", ErrInvalidProjection)
+	}
+	return nil
+}
+
+func (r RecencyWeighted) Project(player Player, date time.Time) float64 {
+
+	total, weight := 0.0, 0.0
+	for _, window := range []struct{ weight, points float64 }{
+		{r.Season, player.AvgPoints},
+		{r.Last30, player.AvgLast30},
+		{r.Last15, player.AvgLast15},
+		{r.Last7, player.AvgLast7},
+	} {
+		if window.points > 0 && window.weight > 0 {
+			total += window.weight * window.points
+			weight += window.weight
+		}
+	}
+
+	if weight == 0 {
+		return player.AvgPoints
+	}
+	return total / weight
+}
+
+// Projector that reads projections from an outside source, keyed by player name and then by date as YYYY-MM-DD. Players or dates
+// without a projection fall back to Fallback, or the season average if there is none
+type FileProjector struct {
+	Projections map[string]map[string]float64
+	Fallback    Projector
+}
+
+// Function to load projections from a JSON file
+func LoadFileProjector(path string) (*FileProjector, error) {
+
+	var projections map[string]map[string]float64
+	if err := readJSON(path, &projections); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidProjection, err)
+	}
+	return &FileProjector{Projections: projections}, nil
+}
+
+func (f *FileProjector) Project(player Player, date time.Time) float64 {
+	if points, ok := f.Projections[player.Name][date.Format(RequestDateLayout)]; ok {
+		return points
+	}
+	if f.Fallback == nil {
+		return player.AvgPoints
+	}
+	return f.Fallback.Project(player, date)
+}
@@ -0,0 +1,111 @@
+package data
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Interface for anything that can supply the roster and the free agents for a team in a league, or just the roster for teams we don't stream for
+type PlayerProvider interface {
+	FetchPlayers(ctx context.Context, league LeagueInfo, fa_count int) (map[string]Player, []Player, error)
+	FetchRoster(ctx context.Context, league LeagueInfo) (map[string]Player, error)
+}
+
+// Provider that fetches players from the cv-backend service, using DefaultClient unless a client is given
+type BackendProvider struct {
+	Client *BackendClient
+}
+
+func (b BackendProvider) FetchPlayers(ctx context.Context, league LeagueInfo, fa_count int) (map[string]Player, []Player, error) {
+	if b.Client == nil {
+		return DefaultClient.FetchData(ctx, league, fa_count)
+	}
+	return b.Client.FetchData(ctx, league, fa_count)
+}
+
+func (b BackendProvider) FetchRoster(ctx context.Context, league LeagueInfo) (map[string]Player, error) {
+	if b.Client == nil {
+		return DefaultClient.FetchRoster(ctx, league)
+	}
+	return b.Client.FetchRoster(ctx, league)
+}
+
+// Provider that reads players from local JSON files, with the roster keyed by player name as in resources/mock_roster.json
+type FileProvider struct {
+	RosterPath     string
+	FreeAgentsPath string
+}
+
+func (f FileProvider) FetchPlayers(ctx context.Context, league LeagueInfo, fa_count int) (map[string]Player, []Player, error) {
+
+	var roster_map map[string]Player
+	if err := readJSON(f.RosterPath, &roster_map); err != nil {
+		return nil, nil, err
+	}
+
+	var free_agents []Player
+	if err := readJSON(f.FreeAgentsPath, &free_agents); err != nil {
+		return nil, nil, err
+	}
+
+	return roster_map, limitFreeAgents(free_agents, fa_count), nil
+}
+
+func (f FileProvider) FetchRoster(ctx context.Context, league LeagueInfo) (map[string]Player, error) {
+
+	var roster_map map[string]Player
+	if err := readJSON(f.RosterPath, &roster_map); err != nil {
+		return nil, err
+	}
+
+	return roster_map, nil
+}
+
+// Provider that serves a fixed set of players from memory
+type MemoryProvider struct {
+	Roster     map[string]Player
+	FreeAgents []Player
+}
+
+func (m MemoryProvider) FetchPlayers(ctx context.Context, league LeagueInfo, fa_count int) (map[string]Player, []Player, error) {
+
+	// Copy the players so that callers can't modify the provider's data
+	roster_map := make(map[string]Player, len(m.Roster))
+	for name, player := range m.Roster {
+		roster_map[name] = player
+	}
+	free_agents := make([]Player, len(m.FreeAgents))
+	copy(free_agents, m.FreeAgents)
+
+	return roster_map, limitFreeAgents(free_agents, fa_count), nil
+}
+
+func (m MemoryProvider) FetchRoster(ctx context.Context, league LeagueInfo) (map[string]Player, error) {
+	roster_map, _, err := m.FetchPlayers(ctx, league, 0)
+	return roster_map, err
+}
+
+// Function to keep only the first fa_count free agents, matching how many the backend would have returned
+func limitFreeAgents(free_agents []Player, fa_count int) []Player {
+	if fa_count > 0 && len(free_agents) > fa_count {
+		return free_agents[:fa_count]
+	}
+	return free_agents
+}
+
+// Function to read a JSON file into the given value
+func readJSON(path string, value any) error {
+
+	json_data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", path, err)
+	}
+
+	if err := json.Unmarshal(json_data, value); err != nil {
+		return fmt.Errorf("decoding %s: %w", path, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,132 @@
+package data
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// Schedule files are named after the season they cover, e.g. schedule24-25.json
+var schedule_file_pattern = regexp.MustCompile(`^schedule(\d{2})-(\d{2})\.json$`)
+
+// Struct for the schedules of every season the server knows about, keyed by the year ESPN uses for the season.
+// ESPN names a season after the year it ends in, so the 2024-25 season is 2025
+type ScheduleRegistry struct {
+	mu      sync.RWMutex
+	seasons map[int]*SeasonSchedule
+}
+
+// Function to create an empty registry
+func NewScheduleRegistry() *ScheduleRegistry {
+	return &ScheduleRegistry{seasons: make(map[int]*SeasonSchedule)}
+}
+
+// Function to load every schedule file in a directory, replacing seasons that were already loaded
+func (r *ScheduleRegistry) LoadDir(dir string) error {
+
+	paths, err := filepath.Glob(filepath.Join(dir, "schedule*.json"))
+	if err != nil {
+		return err
+	}
+
+	loaded := 0
+	for _, path := range paths {
+		year, ok := SeasonYear(filepath.Base(path))
+		if !ok {
+			continue
+		}
+
+		schedule, err := ReadSchedule(path)
+		if err != nil {
+			return err
+		}
+		r.Add(year, schedule)
+		loaded++
+	}
+
+	if loaded == 0 {
+		return fmt.Errorf("no schedule files found in %s", dir)
+	}
+	return nil
+}
+
+// Function to add the schedule for a season
+func (r *ScheduleRegistry) Add(year int, schedule *SeasonSchedule) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.seasons[year] = schedule
+}
+
+// Function to get the schedule for a season. A year of 0 means the latest season
+func (r *ScheduleRegistry) Season(year int) (*SeasonSchedule, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if year == 0 {
+		if years := r.years(); len(years) > 0 {
+			year = years[len(years)-1]
+		}
+	}
+
+	if schedule, ok := r.seasons[year]; ok {
+		return schedule, nil
+	}
+	return nil, fmt.Errorf("%w: %d", ErrSeasonNotFound, year)
+}
+
+// Function to get the years of every loaded season in ascending order
+func (r *ScheduleRegistry) Years() []int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.years()
+}
+
+func (r *ScheduleRegistry) years() []int {
+	years := make([]int, 0, len(r.seasons))
+	for year := range r.seasons {
+		years = append(years, year)
+	}
+	sort.Ints(years)
+	return years
+}
+
+// Function to get the ESPN year of the season a schedule file covers from its name
+func SeasonYear(file_name string) (int, bool) {
+
+	match := schedule_file_pattern.FindStringSubmatch(file_name)
+	if match == nil {
+		return 0, false
+	}
+
+	start, _ := strconv.Atoi(match[1])
+	end, _ := strconv.Atoi(match[2])
+	if end != (start+1)%100 {
+		return 0, false
+	}
+	return 2000 + start + 1, true
+}
+
+// Function to read a schedule file without touching ScheduleMap
+func ReadSchedule(path string) (*SeasonSchedule, error) {
+
+	bytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var schedule SeasonSchedule
+	if err := json.Unmarshal(bytes, &schedule); err != nil {
+		return nil, fmt.Errorf("decoding %s: %w", path, err)
+	}
+	if len(schedule.Schedule) == 0 {
+		return nil, fmt.Errorf("%s has no weeks", path)
+	}
+	return &schedule, nil
+}
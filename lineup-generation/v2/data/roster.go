@@ -0,0 +1,209 @@
+package data
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// Kinds of roster slot. Only starting slots are filled when slotting a lineup; bench and IR slots hold everyone else
+const (
+	SlotStarter = "starter"
+	SlotBench   = "bench"
+	SlotIR      = "ir"
+)
+
+// Ways of choosing who starts on a day
+const (
+	SlottingRestrictive = "restrictive" // Fill the most restrictive slots, leaving flexible slots open for streamers
+	SlottingPoints      = "points"      // Start the players projected to score the most, using restrictiveness to break ties
+)
+
+// Function to check that a slotting mode is one the optimizer knows, where empty means the default
+func ValidateSlotting(slotting string) error {
+	switch slotting {
+	case "", SlottingRestrictive, SlottingPoints:
+		return nil
+	}
+	return fmt.Errorf("%w: slotting must be %q or %q, got %q", ErrInvalidSlotting, SlottingRestrictive, SlottingPoints, slotting)
+}
+
+// Struct for a kind of roster slot, e.g. 3 UT slots that any of PG, SG, SF, PF and C can fill. Priority is how restrictive
+// the slot is: players are funneled into high priority slots first so that flexible slots stay open for streamers
+type RosterSlot struct {
+	Name     string   `json:"name"`
+	Eligible []string `json:"eligible"`
+	Count    int      `json:"count"`
+	Priority int      `json:"priority"`
+	Kind     string   `json:"kind"`
+}
+
+// Struct for a league's roster layout
+type RosterTemplate struct {
+	Slots []RosterSlot `json:"slots"`
+}
+
+// Function to get the standard ESPN layout: one of each position, G, F, 3 UT, 3 bench and 1 IR
+func DefaultRosterTemplate() *RosterTemplate {
+	return &RosterTemplate{Slots: []RosterSlot{
+		{Name: "PG", Eligible: []string{"PG"}, Count: 1, Priority: 5},
+		{Name: "SG", Eligible: []string{"SG"}, Count: 1, Priority: 5},
+		{Name: "SF", Eligible: []string{"SF"}, Count: 1, Priority: 5},
+		{Name: "PF", Eligible: []string{"PF"}, Count: 1, Priority: 5},
+		{Name: "C", Eligible: []string{"C"}, Count: 1, Priority: 3},
+		{Name: "G", Eligible: []string{"PG", "SG"}, Count: 1, Priority: 4},
+		{Name: "F", Eligible: []string{"SF", "PF"}, Count: 1, Priority: 4},
+		{Name: "UT", Eligible: []string{"PG", "SG", "SF", "PF", "C"}, Count: 3, Priority: 2},
+		{Name: "BE", Count: 3, Priority: 1, Kind: SlotBench},
+		{Name: "IR", Count: 1, Kind: SlotIR},
+	}}
+}
+
+// Function to check that the template makes sense
+func (r *RosterTemplate) Validate() error {
+
+	names := make(map[string]bool)
+	starters := 0
+	for _, slot := range r.Slots {
+		switch {
+		case slot.Name == "":
+			return fmt.Errorf("%w: slot without a name", ErrInvalidRosterTemplate)
+		case names[slot.Name]:
+			return fmt.Errorf("%w: slot %s appears twice", ErrInvalidRosterTemplate, slot.Name)
+		case slot.Count < 1:
+			return fmt.Errorf("%w: slot %s needs a count of at least 1", ErrInvalidRosterTemplate, slot.Name)
+		case slot.Priority < 0:
+			return fmt.Errorf("%w: slot %s has a negative priority", ErrInvalidRosterTemplate, slot.Name)
+		}
+
+		switch slot.Kind {
+		case "", SlotStarter:
+			if len(slot.Eligible) == 0 {
+				return fmt.Errorf("%w: starting slot %s has no eligible positions", ErrInvalidRosterTemplate, slot.Name)
+			}
+			starters++
+		case SlotBench, SlotIR:
+		default:
+			return fmt.Errorf("%w: slot %s has unknown kind %q", ErrInvalidRosterTemplate, slot.Name, slot.Kind)
+		}
+		names[slot.Name] = true
+	}
+
+	if starters == 0 {
+		return fmt.Errorf("%w: no starting slots", ErrInvalidRosterTemplate)
+	}
+	return nil
+}
+
+// Function to check if a slot is filled when slotting a lineup
+func (s RosterSlot) IsStarter() bool {
+	return s.Kind == "" || s.Kind == SlotStarter
+}
+
+// Function to get the names of the individual slots, numbering them when there is more than one (UT1, UT2, UT3)
+func (s RosterSlot) SlotNames() []string {
+	if s.Count == 1 {
+		return []string{s.Name}
+	}
+	names := make([]string, s.Count)
+	for i := range names {
+		names[i] = s.Name + strconv.Itoa(i+1)
+	}
+	return names
+}
+
+// Function to get the names of the slots of the given kind in the order they are declared
+func (r *RosterTemplate) SlotsOfKind(kind string) []string {
+	names := []string{}
+	for _, slot := range r.Slots {
+		if slot.Kind == kind || (kind == SlotStarter && slot.IsStarter()) {
+			names = append(names, slot.SlotNames()...)
+		}
+	}
+	return names
+}
+
+// Function to get the starting slots in the order they are displayed
+func (r *RosterTemplate) StarterSlots() []string {
+	return r.SlotsOfKind(SlotStarter)
+}
+
+// Function to get the starting slots from most to least restrictive, which is the order they are filled in
+func (r *RosterTemplate) SlotOrder() []string {
+	slots := make([]RosterSlot, 0, len(r.Slots))
+	for _, slot := range r.Slots {
+		if slot.IsStarter() {
+			slots = append(slots, slot)
+		}
+	}
+	sort.SliceStable(slots, func(i, j int) bool {
+		return slots[i].Priority > slots[j].Priority
+	})
+
+	order := []string{}
+	for _, slot := range slots {
+		order = append(order, slot.SlotNames()...)
+	}
+	return order
+}
+
+// Function to get the priority of an individual slot, e.g. UT2
+func (r *RosterTemplate) Priority(slot_name string) int {
+	for _, slot := range r.Slots {
+		for _, name := range slot.SlotNames() {
+			if name == slot_name {
+				return slot.Priority
+			}
+		}
+	}
+	return 0
+}
+
+// Function to get the highest restrictiveness score that the given number of players could reach, which is the sum of the top starting slot priorities
+func (r *RosterTemplate) MaxScore(num_players int) int {
+	priorities := []int{}
+	for _, slot := range r.Slots {
+		if slot.IsStarter() {
+			for range slot.Count {
+				priorities = append(priorities, slot.Priority)
+			}
+		}
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(priorities)))
+
+	score := 0
+	for i := 0; i < num_players && i < len(priorities); i++ {
+		score += priorities[i]
+	}
+	return score
+}
+
+// Function to get the starting slots a player can fill from the positions they play, most restrictive first. Slot names
+// that ESPN lists as positions (G, UT1, ...) are ignored in favor of what the template says the slot accepts
+func (r *RosterTemplate) EligibleSlots(positions []string) []string {
+
+	plays := make(map[string]bool, len(positions))
+	for _, position := range positions {
+		plays[position] = true
+	}
+
+	slots := []string{}
+	for _, slot := range r.Slots {
+		if !slot.IsStarter() {
+			continue
+		}
+		for _, eligible := range slot.Eligible {
+			if plays[eligible] {
+				slots = append(slots, slot.SlotNames()...)
+				break
+			}
+		}
+	}
+	return slots
+}
+
+// Function to rewrite a player's valid positions as the starting slots they can fill in this template
+func (r *RosterTemplate) FitPlayer(player Player) Player {
+	player.ValidPositions = r.EligibleSlots(player.ValidPositions)
+	return player
+}
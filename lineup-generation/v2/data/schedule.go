@@ -6,8 +6,15 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"time"
 )
 
+// Layout of the dates in schedule files
+const DateLayout = "01/02/2006"
+
+// Layout of the dates in requests
+const RequestDateLayout = "2006-01-02"
+
 // Struct for JSON schedule file that is used to get days a player is playing
 type WeekSchedule struct {
 	StartDate     string           	   	  	 `json:"startDate"`
@@ -21,6 +28,7 @@ type SeasonSchedule struct {
 	Schedule map[string]WeekSchedule `json:"schedule"`
 }
 
+// Schedule of a single season for legacy callers and the mock data. The server looks schedules up in a ScheduleRegistry instead
 var ScheduleMap SeasonSchedule
 
 func InitSchedule(path string) {
@@ -82,4 +90,94 @@ func (w *WeekSchedule) GetEndDate() string {
 
 func (w *WeekSchedule) GetGameSpan() int {
 	return w.GameSpan
-}
\ No newline at end of file
+}
+
+// Function to get the first day of the week
+func (w *WeekSchedule) Start() (time.Time, error) {
+	return time.Parse(DateLayout, w.StartDate)
+}
+
+// Function to get the last day of the week
+func (w *WeekSchedule) End() (time.Time, error) {
+	return time.Parse(DateLayout, w.EndDate)
+}
+
+// Function to list every date in the week, in order, so that index i is day i
+func (w *WeekSchedule) Dates() ([]time.Time, error) {
+
+	start, err := w.Start()
+	if err != nil {
+		return nil, err
+	}
+
+	dates := make([]time.Time, w.GameSpan + 1)
+	for i := range dates {
+		dates[i] = start.AddDate(0, 0, i)
+	}
+	return dates, nil
+}
+
+// Function to list the dates of a week by its number
+func (s *SeasonSchedule) WeekDates(week string) ([]time.Time, error) {
+
+	week_schedule, ok := s.Schedule[week]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrWeekNotFound, week)
+	}
+	return week_schedule.Dates()
+}
+
+// Function to find the week a date falls in and how many days into the week it is. Only the calendar day of date is used
+func (s *SeasonSchedule) WeekForDate(date time.Time) (string, int, error) {
+
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	for week, week_schedule := range s.Schedule {
+		start, err := week_schedule.Start()
+		if err != nil {
+			return "", 0, err
+		}
+		end, err := week_schedule.End()
+		if err != nil {
+			return "", 0, err
+		}
+
+		if !day.Before(start) && !day.After(end) {
+			return week, int(day.Sub(start).Hours() / 24), nil
+		}
+	}
+
+	return "", 0, fmt.Errorf("%w: %s", ErrDateNotInSeason, day.Format(RequestDateLayout))
+}
+
+// Function to check whether a team plays on a date
+func (s *SeasonSchedule) TeamPlaysOn(team string, date time.Time) bool {
+
+	week, day, err := s.WeekForDate(date)
+	if err != nil {
+		return false
+	}
+	return s.IsPlaying(week, day, team)
+}
+
+// Function to work out which week a request is for and which day of it the request was made on. An explicit week wins and starts
+// on day 0, then an explicit date (YYYY-MM-DD), then now
+func (s *SeasonSchedule) ResolveWeek(week string, date string, now time.Time) (string, int, error) {
+
+	if week != "" {
+		if _, ok := s.Schedule[week]; !ok {
+			return "", 0, fmt.Errorf("%w: %s", ErrWeekNotFound, week)
+		}
+		return week, 0, nil
+	}
+
+	day := now
+	if date != "" {
+		parsed, err := time.Parse(RequestDateLayout, date)
+		if err != nil {
+			return "", 0, fmt.Errorf("%w: %q", ErrInvalidDate, date)
+		}
+		day = parsed
+	}
+
+	return s.WeekForDate(day)
+}
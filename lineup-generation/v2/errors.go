@@ -0,0 +1,72 @@
+package main
+
+import (
+	"errors"
+	"context"
+	"net/http"
+
+	d "v2/data"
+	u "v2/utils"
+)
+
+// Struct for the JSON body returned when a request fails
+type ErrorResponse struct {
+	Error string `json:"error"`
+	Code  string `json:"code"`
+}
+
+// Function to map an error from the optimizer to an HTTP status code and a machine-readable error code
+func ErrorStatus(err error) (int, string) {
+
+	switch {
+	case errors.Is(err, u.ErrInvalidOptimizer):
+		return http.StatusBadRequest, "invalid_optimizer"
+	case errors.Is(err, d.ErrBadCredentials):
+		return http.StatusUnauthorized, "bad_credentials"
+	case errors.Is(err, d.ErrLeagueNotFound):
+		return http.StatusNotFound, "league_not_found"
+	case errors.Is(err, d.ErrTeamNotFound):
+		return http.StatusNotFound, "team_not_found"
+	case errors.Is(err, d.ErrSeasonNotFound):
+		return http.StatusNotFound, "season_not_found"
+	case errors.Is(err, d.ErrWeekNotFound):
+		return http.StatusNotFound, "week_not_found"
+	case errors.Is(err, d.ErrDateNotInSeason):
+		return http.StatusNotFound, "date_not_in_season"
+	case errors.Is(err, d.ErrInvalidDate):
+		return http.StatusBadRequest, "invalid_date"
+	case errors.Is(err, d.ErrInvalidStartDay):
+		return http.StatusBadRequest, "invalid_start_day"
+	case errors.Is(err, d.ErrInvalidAcquisitions):
+		return http.StatusBadRequest, "invalid_acquisitions"
+	case errors.Is(err, d.ErrInvalidAcquisitionLimit):
+		return http.StatusBadRequest, "invalid_acquisition_limit"
+	case errors.Is(err, d.ErrInvalidRosterTemplate):
+		return http.StatusBadRequest, "invalid_roster_template"
+	case errors.Is(err, d.ErrInvalidPlayProbabilities):
+		return http.StatusBadRequest, "invalid_play_probabilities"
+	case errors.Is(err, d.ErrInvalidProjection):
+		return http.StatusBadRequest, "invalid_projection"
+	case errors.Is(err, d.ErrInvalidCategories):
+		return http.StatusBadRequest, "invalid_categories"
+	case errors.Is(err, d.ErrInvalidSlotting):
+		return http.StatusBadRequest, "invalid_slotting"
+	case errors.Is(err, d.ErrBackendUnavailable):
+		return http.StatusBadGateway, "backend_unavailable"
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return http.StatusRequestTimeout, "cancelled"
+	default:
+		return http.StatusInternalServerError, "internal_error"
+	}
+}
+
+// Function to write an error as a JSON body with the matching status code
+func WriteError(w http.ResponseWriter, err error) {
+	status, code := ErrorStatus(err)
+	WriteJSON(w, status, ErrorResponse{Error: err.Error(), Code: code})
+}
+
+// Function to write a JSON error body for a request that couldn't be decoded
+func WriteBadRequest(w http.ResponseWriter, message string) {
+	WriteJSON(w, http.StatusBadRequest, ErrorResponse{Error: message, Code: "bad_request"})
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"time"
+	"net/http"
+	"encoding/json"
+
+	"v2/jobs"
+	u "v2/utils"
+)
+
+// Limits for the asynchronous job subsystem
+const (
+	JobWorkers   = 2
+	JobQueueSize = 32
+	JobTTL       = 30 * time.Minute
+)
+
+// Function to register the routes for creating, polling and cancelling optimization jobs
+func RegisterJobRoutes(manager *jobs.Manager) {
+
+	// Handle preflight requests for both routes
+	preflight := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, DELETE")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+	}
+	http.HandleFunc("OPTIONS /jobs", preflight)
+	http.HandleFunc("OPTIONS /jobs/{id}", preflight)
+
+	// Create a job and return its ID right away
+	http.HandleFunc("POST /jobs", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+
+		var request u.ReqBody
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			fmt.Println(err)
+			WriteBadRequest(w, "Failed to decode request body")
+			return
+		}
+
+		// Reject bad optimizer settings now rather than when the job runs
+		if _, err := request.Optimizer.Resolve(); err != nil {
+			WriteError(w, err)
+			return
+		}
+
+		job, err := manager.Submit(request)
+		if errors.Is(err, jobs.ErrQueueFull) {
+			WriteJSON(w, http.StatusServiceUnavailable, ErrorResponse{Error: err.Error(), Code: "queue_full"})
+			return
+		} else if err != nil {
+			http.Error(w, "Failed to create job", http.StatusInternalServerError)
+			return
+		}
+
+		WriteJSON(w, http.StatusAccepted, job)
+	})
+
+	// Get the status, progress and (once finished) the result of a job
+	http.HandleFunc("GET /jobs/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+
+		job, ok := manager.Get(r.PathValue("id"))
+		if !ok {
+			WriteJSON(w, http.StatusNotFound, ErrorResponse{Error: "Job not found", Code: "job_not_found"})
+			return
+		}
+
+		WriteJSON(w, http.StatusOK, job)
+	})
+
+	// Cancel a job
+	http.HandleFunc("DELETE /jobs/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+
+		job, ok := manager.Cancel(r.PathValue("id"))
+		if !ok {
+			WriteJSON(w, http.StatusNotFound, ErrorResponse{Error: "Job not found", Code: "job_not_found"})
+			return
+		}
+
+		WriteJSON(w, http.StatusOK, job)
+	})
+}
+
+// Function to write a JSON-encoded value with the given status code
+func WriteJSON(w http.ResponseWriter, status int, value any) {
+
+	json_data, err := json.Marshal(value)
+	if err != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(json_data)
+}
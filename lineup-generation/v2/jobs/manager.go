@@ -0,0 +1,279 @@
+package jobs
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"sync"
+	"time"
+	p "v2/population"
+	u "v2/utils"
+)
+
+// Lifecycle states of an optimization job
+type Status string
+
+const (
+	StatusQueued    Status = "queued"
+	StatusRunning   Status = "running"
+	StatusDone      Status = "done"
+	StatusFailed    Status = "failed"
+	StatusCancelled Status = "cancelled"
+)
+
+var ErrQueueFull = errors.New("job queue is full")
+
+// Function that actually runs the optimization for a job, reporting progress as it goes
+type RunFunc func(ctx context.Context, req u.ReqBody, on_progress p.ProgressFunc) (u.Response, error)
+
+// Struct for the state of a single job, guarded by the manager's mutex
+type Job struct {
+	ID          string
+	Status      Status
+	Generation  int
+	BestFitness float64
+	Result      *u.Response
+	Err         string
+	CreatedAt   time.Time
+	FinishedAt  time.Time
+
+	req    u.ReqBody
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// Struct for the read-only view of a job that is handed out to callers
+type Snapshot struct {
+	ID          string      `json:"id"`
+	Status      Status      `json:"status"`
+	Generation  int         `json:"generation"`
+	BestFitness float64     `json:"best_fitness"`
+	Result      *u.Response `json:"result,omitempty"`
+	Error       string      `json:"error,omitempty"`
+	CreatedAt   time.Time   `json:"created_at"`
+}
+
+// Struct for running jobs on a bounded pool of workers and keeping their results in memory until they expire
+type Manager struct {
+	mu    sync.Mutex
+	jobs  map[string]*Job
+	queue chan *Job
+	run   RunFunc
+	ttl   time.Duration
+	stop  chan struct{}
+	wg    sync.WaitGroup
+}
+
+// Function to create a new manager and start its workers and janitor
+func NewManager(workers int, queue_size int, ttl time.Duration, run RunFunc) *Manager {
+
+	m := &Manager{
+		jobs:  make(map[string]*Job),
+		queue: make(chan *Job, queue_size),
+		run:   run,
+		ttl:   ttl,
+		stop:  make(chan struct{}),
+	}
+
+	for i := 0; i < workers; i++ {
+		m.wg.Add(1)
+		go m.worker()
+	}
+
+	m.wg.Add(1)
+	go m.janitor()
+
+	return m
+}
+
+// Function to queue a new job, failing fast rather than blocking when every worker is busy and the queue is full
+func (m *Manager) Submit(req u.ReqBody) (Snapshot, error) {
+
+	id, err := NewID()
+	if err != nil {
+		return Snapshot{}, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	job := &Job{ID: id, Status: StatusQueued, CreatedAt: time.Now(), req: req, ctx: ctx, cancel: cancel}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	select {
+	case m.queue <- job:
+		m.jobs[id] = job
+		return job.snapshot(), nil
+	default:
+		cancel()
+		return Snapshot{}, ErrQueueFull
+	}
+}
+
+// Function to get the current state of a job
+func (m *Manager) Get(id string) (Snapshot, bool) {
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	job, ok := m.jobs[id]
+	if !ok {
+		return Snapshot{}, false
+	}
+	return job.snapshot(), true
+}
+
+// Function to cancel a job. Finished jobs are left as they are
+func (m *Manager) Cancel(id string) (Snapshot, bool) {
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	job, ok := m.jobs[id]
+	if !ok {
+		return Snapshot{}, false
+	}
+
+	if job.Status == StatusQueued || job.Status == StatusRunning {
+		job.cancel()
+		job.finish(StatusCancelled)
+	}
+	return job.snapshot(), true
+}
+
+// Function to remove the finished jobs whose results have outlived the TTL, returning how many were removed
+func (m *Manager) Expire(now time.Time) int {
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	removed := 0
+	for id, job := range m.jobs {
+		if !job.FinishedAt.IsZero() && now.Sub(job.FinishedAt) > m.ttl {
+			delete(m.jobs, id)
+			removed++
+		}
+	}
+	return removed
+}
+
+// Function to stop the workers and the janitor, cancelling anything that is still running
+func (m *Manager) Close() {
+
+	m.mu.Lock()
+	for _, job := range m.jobs {
+		job.cancel()
+	}
+	m.mu.Unlock()
+
+	close(m.stop)
+	m.wg.Wait()
+}
+
+// Function that pulls jobs off the queue and runs them one at a time
+func (m *Manager) worker() {
+	defer m.wg.Done()
+
+	for {
+		select {
+		case <-m.stop:
+			return
+		case job := <-m.queue:
+			m.execute(job)
+		}
+	}
+}
+
+// Function to run a single job and record its outcome
+func (m *Manager) execute(job *Job) {
+
+	// Jobs cancelled while they were still queued never start
+	m.mu.Lock()
+	if job.Status != StatusQueued {
+		m.mu.Unlock()
+		return
+	}
+	job.Status = StatusRunning
+	m.mu.Unlock()
+
+	on_progress := func(progress p.Progress) {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+
+		// Islands report concurrently, so keep the furthest generation and the best fitness seen so far
+		if progress.Generation > job.Generation {
+			job.Generation = progress.Generation
+		}
+		if progress.BestFitness > job.BestFitness {
+			job.BestFitness = progress.BestFitness
+		}
+	}
+
+	response, err := m.run(job.ctx, job.req, on_progress)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// A cancelled job keeps its cancelled status even if the run managed to finish
+	if job.Status != StatusRunning {
+		return
+	}
+	if err != nil {
+		job.Err = err.Error()
+		job.finish(StatusFailed)
+		return
+	}
+	job.Result = &response
+	job.finish(StatusDone)
+}
+
+// Function that periodically removes expired jobs
+func (m *Manager) janitor() {
+	defer m.wg.Done()
+
+	interval := m.ttl / 2
+	if interval < time.Second {
+		interval = time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-m.stop:
+			return
+		case now := <-ticker.C:
+			m.Expire(now)
+		}
+	}
+}
+
+// Function to move a job into a final state
+func (j *Job) finish(status Status) {
+	j.Status = status
+	j.FinishedAt = time.Now()
+	j.cancel()
+}
+
+// Function to copy the job into a snapshot
+func (j *Job) snapshot() Snapshot {
+	return Snapshot{
+		ID:          j.ID,
+		Status:      j.Status,
+		Generation:  j.Generation,
+		BestFitness: j.BestFitness,
+		Result:      j.Result,
+		Error:       j.Err,
+		CreatedAt:   j.CreatedAt,
+	}
+}
+
+// Function to generate a random job ID
+func NewID() (string, error) {
+	bytes := make([]byte, 16)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(bytes), nil
+}
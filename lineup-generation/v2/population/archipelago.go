@@ -0,0 +1,166 @@
+package population
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	t "v2/team"
+	u "v2/utils"
+)
+
+// Struct for running several populations (islands) side by side and periodically migrating their best chromosomes between them
+type Archipelago struct {
+	Islands           []*EvolutionManager
+	Topology          string
+	MigrationInterval int
+	NumEmigrants      int
+}
+
+// Function to create a new archipelago with one population per island. If ctx is cancelled, the islands created so far are kept and the context's error is returned
+func InitArchipelago(ctx context.Context, bt *t.BaseTeam, config u.OptimizerConfig, rng *rand.Rand) (*Archipelago, error) {
+
+	a := &Archipelago{
+		Islands:           make([]*EvolutionManager, config.NumIslands),
+		Topology:          config.MigrationTopology,
+		MigrationInterval: config.MigrationInterval,
+		NumEmigrants:      config.NumEmigrants,
+	}
+
+	// Islands are created one after another so that each one draws its seeds from rng in a fixed order
+	for i := range a.Islands {
+		island, err := InitPopulation(ctx, bt, config, rng)
+		if err != nil {
+			a.Islands = append(a.Islands[:i], island)
+			island.Island = i
+			return a, err
+		}
+		a.Islands[i] = island
+		a.Islands[i].Island = i
+	}
+
+	return a, nil
+}
+
+// Function to register a progress callback on every island
+func (a *Archipelago) SetProgressFunc(on_progress ProgressFunc) {
+	for _, island := range a.Islands {
+		island.OnProgress = on_progress
+	}
+}
+
+// Function to swap in a different fitness function on every island
+func (a *Archipelago) SetFitness(fitness FitnessFunc) {
+	for _, island := range a.Islands {
+		island.SetFitness(fitness)
+	}
+}
+
+// Function to evolve every island concurrently for the given number of generations, migrating between them every MigrationInterval generations.
+// Stops early with the context's error if ctx is cancelled
+func (a *Archipelago) Evolve(ctx context.Context, bt *t.BaseTeam, generations int) error {
+
+	for done := 0; done < generations; {
+
+		// Evolve until the next migration or until the generations run out
+		epoch := a.MigrationInterval
+		if epoch <= 0 || epoch > generations-done {
+			epoch = generations - done
+		}
+
+		var wg sync.WaitGroup
+		wg.Add(len(a.Islands))
+		for _, island := range a.Islands {
+			go func(ev *EvolutionManager) {
+				defer wg.Done()
+				for i := 0; i < epoch; i++ {
+					if ev.Evolve(ctx, bt) != nil {
+						return
+					}
+				}
+			}(island)
+		}
+		wg.Wait()
+		done += epoch
+
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		// Migrate between epochs but not after the last one since the islands are about to be merged anyway
+		if done < generations {
+			a.Migrate()
+		}
+	}
+
+	return nil
+}
+
+// Function to send copies of each island's best chromosomes to its neighbors, replacing their worst chromosomes
+func (a *Archipelago) Migrate() {
+
+	if len(a.Islands) < 2 || a.NumEmigrants <= 0 {
+		return
+	}
+
+	// Sort every island so that the best chromosomes are at the end and the worst at the start
+	for _, island := range a.Islands {
+		island.SortByFitness()
+	}
+
+	// Collect the immigrants for each island before replacing anything so that every island sends from its pre-migration population
+	immigrants := make([][]*Chromosome, len(a.Islands))
+	for i, island := range a.Islands {
+		num_emigrants := min(a.NumEmigrants, island.NumChromosomes)
+		for _, neighbor := range a.Neighbors(i) {
+			for j := 0; j < num_emigrants; j++ {
+				immigrants[neighbor] = append(immigrants[neighbor], island.Population[island.NumChromosomes-1-j].Copy())
+			}
+		}
+	}
+
+	// Replace the worst chromosomes, always keeping the island's own best chromosome
+	for i, island := range a.Islands {
+		num_replaced := min(len(immigrants[i]), island.NumChromosomes-1)
+		for j := 0; j < num_replaced; j++ {
+			island.Population[j] = immigrants[i][j]
+		}
+	}
+}
+
+// Function to get the islands that the given island sends emigrants to
+func (a *Archipelago) Neighbors(index int) []int {
+
+	switch a.Topology {
+	case u.TopologyFull:
+		neighbors := make([]int, 0, len(a.Islands)-1)
+		for i := range a.Islands {
+			if i != index {
+				neighbors = append(neighbors, i)
+			}
+		}
+		return neighbors
+	default:
+		return []int{(index + 1) % len(a.Islands)}
+	}
+}
+
+// Function to combine the islands into a single population
+func (a *Archipelago) Merge() *EvolutionManager {
+
+	first := a.Islands[0]
+	ev := &EvolutionManager{
+		Population: make([]*Chromosome, 0, first.NumChromosomes*len(a.Islands)),
+		Config:     first.Config,
+		Rng:        first.Rng,
+		Island:     MergedIsland,
+		Generation: first.Generation,
+		OnProgress: first.OnProgress,
+		Fitness:    first.Fitness,
+	}
+	for _, island := range a.Islands {
+		ev.Population = append(ev.Population, island.Population...)
+	}
+	ev.NumChromosomes = len(ev.Population)
+
+	return ev
+}
@@ -2,7 +2,6 @@ package population
 
 import (
 	"fmt"
-	"math"
 	"sort"
 	"math/rand"
 	d "v2/data"
@@ -13,33 +12,40 @@ import (
 // Struct for chromosome for genetic algorithm
 type Chromosome struct {
 	Genes 	     	  	[]*Gene
-	FitnessScore	  	int
+	FitnessScore	  	float64
 	TotalAcquisitions int
 	CumProbTracker 	  float64
 	DroppedPlayers    map[string]d.DroppedPlayer
 	CurStreamers 	  	[]d.Player
 	Week			  			string
+	StartDay          int
+	AcquisitionsUsed  int
+	Limit             d.AcquisitionLimit
 }
 
-// Function to create a new chromosome
+// Function to create a new chromosome with a gene for each day that is left in the week. Gene i is for day StartDay + i
 func InitChromosome(bt *t.BaseTeam) *Chromosome {
 	
 	// Create a new chromosome
-	chromosome := &Chromosome{Genes: make([]*Gene, d.ScheduleMap.Schedule[bt.Week].GameSpan + 1), 
+	game_span := bt.GetSchedule().GetGameSpan(bt.Week)
+	chromosome := &Chromosome{Genes: make([]*Gene, game_span + 1 - bt.StartDay), 
 		FitnessScore: 0, 
 		TotalAcquisitions: 0, 
 		CumProbTracker: 0.0, 
 		DroppedPlayers: make(map[string]d.DroppedPlayer),
 		CurStreamers: make([]d.Player, len(bt.StreamablePlayers)),
 		Week: bt.Week,
+		StartDay: bt.StartDay,
+		AcquisitionsUsed: bt.AcquisitionsUsed,
+		Limit: bt.GetAcquisitionLimit(),
 	}
 
 	// Make the initial streamers the current streamers
 	copy(chromosome.CurStreamers, bt.StreamablePlayers)
 
-	// Create a gene for each day in the week
-	for i := 0; i <= d.ScheduleMap.Schedule[bt.Week].GameSpan; i++ {
-		gene := InitGene(bt, i)
+	// Create a gene for each remaining day in the week
+	for i := range chromosome.Genes {
+		gene := InitGene(bt, bt.StartDay + i)
 		chromosome.Genes[i] = gene
 	}
 
@@ -55,16 +61,16 @@ func (c *Chromosome) Populate(bt *t.BaseTeam, rng *rand.Rand) {
 	}
 
 	// Insert random free agents into the genes
-	for day, gene := range c.Genes {
+	for index, gene := range c.Genes {
 		acq_count := (rng.Intn(5) / 2) + rng.Intn(2)
 
 		// Check if there are enough available slots to make acquisitions
-		if len(bt.UnusedPositions[day]) < acq_count {
-			acq_count = len(bt.UnusedPositions[day])
+		if len(bt.UnusedPositions[gene.Day]) < acq_count {
+			acq_count = len(bt.UnusedPositions[gene.Day])
 		}
 
 		// On the first day, make sure you can't drop initial streamers who are playing
-		if non_playing_streamers_count := gene.Bench.GetLength(); day == 0 && acq_count > non_playing_streamers_count {
+		if non_playing_streamers_count := gene.Bench.GetLength(); index == 0 && acq_count > non_playing_streamers_count {
 			acq_count = non_playing_streamers_count
 		}
 
@@ -79,24 +85,34 @@ func (c *Chromosome) Populate(bt *t.BaseTeam, rng *rand.Rand) {
 			acq_count = len(bt.StreamablePlayers)
 		}
 
+		// In hard mode, never make more acquisitions than the league allows
+		if c.Limit.Hard {
+			acq_count = min(acq_count, c.AcquisitionAllowance(index))
+		}
+
 		// Create a map of the current (old) streamers
 		old_streamers := make(map[string]d.Player)
+		old_streamer_order := make([]d.Player, len(c.CurStreamers))
+		copy(old_streamer_order, c.CurStreamers)
 		for _, player := range c.CurStreamers {
 			old_streamers[player.Name] = player
 		}
 
+		// Make room for any players coming back from IR today
+		c.MakeForcedDrops(bt, index)
+
 		// Make acquisitions
 		for i := 0; i < acq_count; i++ {
 			free_agent := gene.FindRandomFreeAgent(bt, c, rng, d.Player{}); if free_agent.Name == "" {
 				break
 			}
 			
-			c.InsertFreeAgent(bt, day, free_agent)
+			c.InsertFreeAgent(bt, index, free_agent)
 
 		}
 
-		// Go through the old streamers and find the ones that were dropped
-		for _, old_player := range old_streamers {
+		// Go through the old streamers in their original order and find the ones that were dropped
+		for _, old_player := range old_streamer_order {
 			if !u.SliceContainsPlayer(c.CurStreamers, &old_player) {
 				c.DroppedPlayers[old_player.Name] = d.DroppedPlayer{Player: old_player, Countdown: 3}
 				gene.DroppedPlayers = append(gene.DroppedPlayers, old_player)
@@ -118,36 +134,57 @@ func (c *Chromosome) Populate(bt *t.BaseTeam, rng *rand.Rand) {
 	}
 }
 
-// Function to insert a free agent into the chromosome
-func (c *Chromosome) InsertFreeAgent(bt *t.BaseTeam, day int, free_agent d.Player) bool {
-	gene := c.Genes[day]
+// Function to insert a free agent into the chromosome from the gene at the given index onwards
+func (c *Chromosome) InsertFreeAgent(bt *t.BaseTeam, index int, free_agent d.Player) bool {
+	gene := c.Genes[index]
 
 	// If it is the first day or there are streamers on the bench, drop the worst bench player and find the best positions for the new player
-	if day == 0 || gene.Bench.GetLength() > 0 {
+	if index == 0 || gene.Bench.GetLength() > 0 {
 
 		dropped_player, ok := gene.DropWorstBenchPlayer(); if !ok {
 			return false
 		}
 
-		c.RemoveStreamer(day, free_agent, dropped_player)
-		c.SlotPlayer(bt, day, len(c.Genes),  free_agent)
+		c.RemoveStreamer(index, free_agent, dropped_player)
+		c.SlotPlayer(bt, index, len(c.Genes),  free_agent)
 	} else {
 		// If there are no streamers on the bench (i.e. the roster is full), drop the worst playing streamer that the free agent can replace and find the best position for the new player
 
 		// Find the worst current streamer that the free agent can replace
-		player_to_drop := c.FindStreamerToDrop(day, free_agent); if player_to_drop == nil {
+		player_to_drop := c.FindStreamerToDrop(index, free_agent); if player_to_drop == nil {
 			fmt.Println("Error finding streamer to drop")
 			return false
 		}
 
 		// Drop the worst streamer and add the free agent
-		c.RemoveStreamer(day, free_agent, *player_to_drop)
-		c.SlotPlayer(bt, day, len(c.Genes), free_agent)
+		c.RemoveStreamer(index, free_agent, *player_to_drop)
+		c.SlotPlayer(bt, index, len(c.Genes), free_agent)
 	}
 
 	return true
 }
 
+// Function to drop the worst streamers on a day when players coming back from IR need their roster spots
+func (c *Chromosome) MakeForcedDrops(bt *t.BaseTeam, index int) {
+	for n := bt.ForcedDrops[c.Genes[index].Day]; n > 0 && len(c.CurStreamers) > 0; n-- {
+
+		// Find the worst current streamer
+		worst := 0
+		for i, streamer := range c.CurStreamers {
+			if streamer.AvgPoints < c.CurStreamers[worst].AvgPoints {
+				worst = i
+			}
+		}
+		dropped_player := c.CurStreamers[worst]
+
+		// Take him off the roster for the rest of the week without anyone replacing him
+		for _, gene := range c.Genes[index:] {
+			gene.RemoveStreamer(dropped_player)
+		}
+		c.CurStreamers = append(c.CurStreamers[:worst], c.CurStreamers[worst+1:]...)
+	}
+}
+
 // Function to find the worst streamer to drop
 func (c *Chromosome) FindStreamerToDrop(day int, player_to_add d.Player) *d.Player {
 	sort.Slice(c.CurStreamers, func(i, j int) bool {
@@ -205,9 +242,10 @@ func (c *Chromosome) SlotPlayer(bt *t.BaseTeam, start int, end int, free_agent d
 
 // Function to decrement the countdown for dropped players
 func (c *Chromosome) DecrementDroppedPlayers() {
-	for _, dropped_player := range c.DroppedPlayers {
+	for name, dropped_player := range c.DroppedPlayers {
 		if dropped_player.Countdown > 0 {
 			dropped_player.Countdown--
+			c.DroppedPlayers[name] = dropped_player
 		} else {
 			delete(c.DroppedPlayers, dropped_player.Player.Name)
 		}
@@ -245,6 +283,7 @@ func (c *Chromosome) Mutate(bt *t.BaseTeam, prob float64, rng *rand.Rand) (d.Pla
 		if dropped_player, ok := c.DroppedPlayers[player_to_drop.Name]; ok {
 			if dropped_player.Countdown > 0 {
 				dropped_player.Countdown--
+				c.DroppedPlayers[player_to_drop.Name] = dropped_player
 			} else {
 				delete(c.DroppedPlayers, player_to_drop.Name)
 			}
@@ -289,10 +328,10 @@ func (c *Chromosome) Mutate(bt *t.BaseTeam, prob float64, rng *rand.Rand) (d.Pla
 // Function to find a random player to drop
 func (c *Chromosome) FindRandomPlayerToDrop(rng *rand.Rand) (d.Player, string, int, int) {
 
-	start := 0
+	start := -1
 	trials := 0
 	test_start := rng.Intn(len(c.Genes))
-	for start == 0 && trials < len(c.Genes) {
+	for start == -1 && trials < len(c.Genes) {
 		if c.Genes[test_start].Acquisitions > 0 {
 			start = test_start
 			break
@@ -301,7 +340,7 @@ func (c *Chromosome) FindRandomPlayerToDrop(rng *rand.Rand) (d.Player, string, i
 			trials++
 		}
 	}
-	if start == 0 {
+	if start == -1 {
 		return d.Player{}, "", -1, -1
 	}
 
@@ -320,14 +359,17 @@ func (c *Chromosome) FindRandomPlayerToDrop(rng *rand.Rand) (d.Player, string, i
 
 }
 
-// Function to add back the non-streamable players to the chromosome for returning
+// Function to add back the non-streamable players to the chromosome for returning, along with whoever is still on IR
 func (c *Chromosome) AddBackNonStreamablePlayers(bt *t.BaseTeam) {
-	for day, gene := range c.Genes {
-		for pos, player := range bt.OptimalSlotting[day] {
+	for _, gene := range c.Genes {
+		for pos, player := range bt.OptimalSlotting[gene.Day] {
 			if player.Name != "" {
 				gene.Roster[pos] = player
 			}
 		}
+		for pos, player := range bt.IRSlotting(gene.Day) {
+			gene.Roster[pos] = player
+		}
 	}
 }
 
@@ -336,13 +378,16 @@ func (c *Chromosome) AddBackNonStreamablePlayers(bt *t.BaseTeam) {
 
 // Function to print the chromosome
 func (c *Chromosome) Print() {
-	order := []string{"PG", "SG", "SF", "PF", "C", "G", "F", "UT1", "UT2", "UT3"}
+	order := []string{}
+	if len(c.Genes) > 0 {
+		order = c.Genes[0].Template.StarterSlots()
+	}
 
 
 	fmt.Println("Total Acquisitions:", c.TotalAcquisitions)
 	for i := 0; i < len(c.Genes); i++ {
 		gene := c.Genes[i]
-		fmt.Println("Day", i)
+		fmt.Println("Day", gene.Day)
 		fmt.Println("New Players", gene.NewPlayers)
 		for _, pos := range order {
 			if val, ok := gene.FreePositions[pos]; ok && val {
@@ -362,22 +407,81 @@ func (c *Chromosome) Print() {
 		
 }
 
-// Function to score the fitness of the chromosome
+// Function to score the fitness of the chromosome with the default fitness function
 func (c *Chromosome) ScoreFitness() {
+	c.FitnessScore = TruncatedFitness{}.Score(c)
+}
 
-	fitness_score := 0.0
-	penalty_factor := 1.0
-
-	if c.TotalAcquisitions > d.ScheduleMap.GetGameSpan(c.Week) + 1 {
-		penalty_factor = 1.0 / math.Pow(1.3, float64(c.TotalAcquisitions - (d.ScheduleMap.GetGameSpan(c.Week) + 1)))
+// Function to get the points the streamers in the chromosome are expected to score over the rest of the week
+func (c *Chromosome) Points() float64 {
+	points := 0.0
+	for _, gene := range c.Genes {
+		for _, player := range gene.Roster {
+			points += gene.ExpectedPoints(player)
+		}
 	}
+	return points
+}
+
+// Function to get the variance of the points the streamers in the chromosome score over the rest of the week, with each game's spread being cv times its projection
+func (c *Chromosome) Variance(cv float64) float64 {
+	variance := 0.0
 	for _, gene := range c.Genes {
 		for _, player := range gene.Roster {
-			fitness_score += player.AvgPoints
+			variance += t.GameVariance(gene.PlayProbability(player), gene.ProjectedPoints(player), cv)
 		}
 	}
+	return variance
+}
+
+// Function to get the number of acquisitions made on each remaining day
+func (c *Chromosome) DailyAcquisitions() []int {
+	daily := make([]int, len(c.Genes))
+	for i, gene := range c.Genes {
+		daily[i] = gene.Acquisitions
+	}
+	return daily
+}
+
+// Function to count the acquisitions that go over the league's limit
+func (c *Chromosome) ExcessAcquisitions() int {
+	return c.Limit.Excess(c.StartDay, c.DailyAcquisitions(), c.AcquisitionsUsed)
+}
+
+// Function to check whether the chromosome stays within the league's acquisition limit
+func (c *Chromosome) IsFeasible() bool {
+	return c.ExcessAcquisitions() == 0
+}
 
-	c.FitnessScore = int(fitness_score * penalty_factor)
+// Function to get how many more acquisitions can be made on the gene at the given index
+func (c *Chromosome) AcquisitionAllowance(index int) int {
+	return c.Limit.Allowance(c.StartDay, c.DailyAcquisitions(), c.AcquisitionsUsed, index)
+}
+
+// Function to create a deep copy of the chromosome that shares no mutable state with the original
+func (c *Chromosome) Copy() *Chromosome {
+	chromosome := &Chromosome{
+		Genes: make([]*Gene, len(c.Genes)),
+		FitnessScore: c.FitnessScore,
+		TotalAcquisitions: c.TotalAcquisitions,
+		CumProbTracker: c.CumProbTracker,
+		DroppedPlayers: make(map[string]d.DroppedPlayer, len(c.DroppedPlayers)),
+		CurStreamers: make([]d.Player, len(c.CurStreamers)),
+		Week: c.Week,
+		StartDay: c.StartDay,
+		AcquisitionsUsed: c.AcquisitionsUsed,
+		Limit: c.Limit,
+	}
+
+	for i, gene := range c.Genes {
+		chromosome.Genes[i] = gene.Copy()
+	}
+	for name, dropped_player := range c.DroppedPlayers {
+		chromosome.DroppedPlayers[name] = dropped_player
+	}
+	copy(chromosome.CurStreamers, c.CurStreamers)
+
+	return chromosome
 }
 
 // Function to return a slimmed down, defreferenced version of the chromosome
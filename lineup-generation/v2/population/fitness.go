@@ -0,0 +1,170 @@
+package population
+
+import (
+	"math"
+	d "v2/data"
+	t "v2/team"
+	u "v2/utils"
+)
+
+// Interface for anything that can score how fit a chromosome is, where a higher score is better
+type FitnessFunc interface {
+	Score(c *Chromosome) float64
+}
+
+// Function to get the fitness function that the optimizer config asks for
+func NewFitnessFunc(config u.OptimizerConfig) FitnessFunc {
+	switch config.Fitness {
+	case u.FitnessPoints:
+		return PointsFitness{}
+	case u.FitnessMoveCost:
+		return MoveCostFitness{Cost: config.MoveCost}
+	case u.FitnessMeanVariance:
+		return MeanVarianceFitness{RiskAversion: config.RiskAversion, GameCV: DefaultGameCV}
+	}
+	return TruncatedFitness{}
+}
+
+// Function to apply the acquisition limit to a score. Going over the limit is penalized for every extra move, or rules the chromosome
+// out entirely in hard mode. Scores can be negative once moves or risk are charged for, so the penalty has to push them down either way
+// and ruling out means the lowest score there is
+func penalize(c *Chromosome, score float64) float64 {
+	if excess := c.ExcessAcquisitions(); excess > 0 {
+		if c.Limit.Hard {
+			return -math.MaxFloat64
+		}
+		return score - math.Abs(score)*(1-1/math.Pow(1.3, float64(excess)))
+	}
+	return score
+}
+
+// Fitness the optimizer has always used: expected points with the acquisition penalty, truncated to a whole number
+type TruncatedFitness struct{}
+
+func (TruncatedFitness) Score(c *Chromosome) float64 {
+	return math.Trunc(penalize(c, c.Points()))
+}
+
+// Fitness that keeps the fractions of a point, so that lineups a fraction of a point apart can be told apart
+type PointsFitness struct{}
+
+func (PointsFitness) Score(c *Chromosome) float64 {
+	return penalize(c, c.Points())
+}
+
+// Fitness that charges Cost points for every acquisition, so that a move has to be worth more than it costs
+type MoveCostFitness struct {
+	Cost float64
+}
+
+func (m MoveCostFitness) Score(c *Chromosome) float64 {
+	return penalize(c, c.Points()-m.Cost*float64(c.TotalAcquisitions))
+}
+
+// How much a player's points swing from game to game, as a fraction of his projection
+const DefaultGameCV = 0.35
+
+// Fitness that trades expected points off against how much they could swing. Each game a player might play adds the variance of
+// his points, which comes from both how well he plays (GameCV of his projection) and whether he plays at all
+type MeanVarianceFitness struct {
+	RiskAversion float64
+	GameCV       float64
+}
+
+func (m MeanVarianceFitness) Score(c *Chromosome) float64 {
+
+	return penalize(c, c.Points()-m.RiskAversion*c.Variance(m.GameCV))
+}
+
+// Fitness that maximizes the chance of winning a points matchup. The margin at the end of the week is taken to be normally
+// distributed, with Lead being how far ahead we are once the points already scored and both teams' core players are counted, and
+// BaseVariance the variance of those core players. Trailing teams are pushed towards risky streamers and leading teams towards safe ones
+type WinProbabilityFitness struct {
+	Lead         float64
+	BaseVariance float64
+	GameCV       float64
+}
+
+// Function to build the win probability fitness for a matchup. points and opponent_points are what each team has already scored this week
+func NewWinProbabilityFitness(bt *t.BaseTeam, opponent *t.BaseTeam, points float64, opponent_points float64) *WinProbabilityFitness {
+	ours, our_variance := bt.ProjectTotal(DefaultGameCV)
+	theirs, their_variance := opponent.ProjectTotal(DefaultGameCV)
+	return &WinProbabilityFitness{Lead: points + ours - opponent_points - theirs, BaseVariance: our_variance + their_variance, GameCV: DefaultGameCV}
+}
+
+func (w *WinProbabilityFitness) Score(c *Chromosome) float64 {
+	return penalize(c, w.WinProbability(c))
+}
+
+// Function to estimate the chance of winning the matchup with a chromosome's streamers
+func (w *WinProbabilityFitness) WinProbability(c *Chromosome) float64 {
+	margin := w.Lead + c.Points()
+	variance := w.BaseVariance + c.Variance(w.GameCV)
+	if variance <= 0 {
+		switch {
+		case margin > 0:
+			return 1
+		case margin < 0:
+			return 0
+		}
+		return 0.5
+	}
+	return 0.5 * math.Erfc(-margin/math.Sqrt(2*variance))
+}
+
+// Fitness for head-to-head category leagues. The weekly totals of everyone who plays, core players included, are projected from
+// their per-game averages and scored either on the totals scaled by the standard deviation of a game across the pool, or by the categories they win against the opponent
+type CategoryFitness struct {
+	League d.CategoryLeague
+	Scales d.CategoryScales
+	Core   d.CategoryStats
+}
+
+// Function to build the category fitness for a team, measuring categories against its roster and free agents
+func NewCategoryFitness(bt *t.BaseTeam, league d.CategoryLeague) *CategoryFitness {
+
+	league = league.WithDefaults()
+	pool := make([]d.Player, 0, len(bt.RosterMap)+len(bt.FreeAgents))
+	for _, player := range bt.RosterMap {
+		pool = append(pool, player)
+	}
+	pool = append(pool, bt.FreeAgents...)
+	f := &CategoryFitness{League: league, Scales: d.NewCategoryScales(league.Categories, pool)}
+
+	// The core players are the same in every chromosome, so their share of the totals is worked out once
+	for day, lineup := range bt.OptimalSlotting {
+		for _, player := range lineup {
+			if player.Name != "" {
+				f.Core.Add(player.Stats, bt.PlayProbability(player, day))
+			}
+		}
+	}
+	return f
+}
+
+// Function to add up the week's projected totals for everyone who plays in the chromosome
+func (f *CategoryFitness) Totals(c *Chromosome) d.CategoryStats {
+	totals := f.Core
+	for _, gene := range c.Genes {
+		for _, player := range gene.Roster {
+			totals.Add(player.Stats, gene.PlayProbability(player))
+		}
+	}
+	return totals
+}
+
+// Function to score a chromosome on the value of the week's totals, or on the categories they win against the opponent
+func (f *CategoryFitness) Score(c *Chromosome) float64 {
+
+	totals := f.Totals(c)
+	value := f.Scales.Value(totals)
+
+	// Categories won only changes in whole steps, so the value of the totals breaks ties between lineups without ever being worth a category
+	score := value
+	if f.League.Scoring == d.ScoringWins {
+		score = d.CategoriesWon(f.League.Categories, totals, *f.League.Opponent) + 0.4*math.Tanh(value/100)
+	}
+
+	// The value of the totals can be negative when turnovers outweigh everything else, which the penalty allows for
+	return penalize(c, score)
+}
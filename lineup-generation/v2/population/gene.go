@@ -3,6 +3,7 @@ package population
 import (
 	"fmt"
 	"math/rand"
+	"sort"
 	d "v2/data"
 	t "v2/team"
 	u "v2/utils"
@@ -17,6 +18,9 @@ type Gene struct {
 	Day     	   	 int
 	Acquisitions   int
 	Bench 		   	 u.Bench
+	Template       *d.RosterTemplate
+	Availability   map[string]float64
+	Projections    map[string]float64
 }
 
 
@@ -32,7 +36,10 @@ func InitGene(bt *t.BaseTeam, day int) *Gene {
 		NewPlayers: make([]d.Player, 0, 6), 
 		Day: day, 
 		Acquisitions: 0,
-		Bench: u.Bench{Players: make([]d.Player, 0, 10)},
+		Bench: u.Bench{Players: make([]d.Player, 0, 10), Projections: bt.Projections[day]},
+		Template: bt.GetRosterTemplate(),
+		Availability: bt.Availability[day],
+		Projections: bt.Projections[day],
 	}
 	
 	return gene
@@ -51,7 +58,13 @@ func (g *Gene) InsertStreamablePlayers(bt *t.BaseTeam) {
 		}
 	}
 
-	for _, streamer := range bt.StreamablePlayers {
+	// Slot the streamers most likely to score first so that they get the open positions
+	streamers := make([]d.Player, len(bt.StreamablePlayers))
+	copy(streamers, bt.StreamablePlayers)
+	sort.SliceStable(streamers, func(i, j int) bool {
+		return g.ExpectedPoints(streamers[i]) > g.ExpectedPoints(streamers[j])
+	})
+	for _, streamer := range streamers {
 		g.SlotPlayer(bt, streamer)
 	}
 }
@@ -62,12 +75,18 @@ func (g *Gene) InsertStreamablePlayers(bt *t.BaseTeam) {
 // Function to slot a player into the gene
 func (g *Gene) SlotPlayer(bt *t.BaseTeam, streamer d.Player) {
 
-	// If the streamer is not playing, add them to the bench
-	if !d.ScheduleMap.IsPlaying(bt.Week, g.Day, streamer.Team) {
+	// If the streamer is not playing or is still injured, add them to the bench
+	if !bt.CanPlay(streamer, g.Day) {
 		g.Bench.AddPlayer(streamer)
 		return
 	}
 
+	// When slotting for points every streamer who plays competes for the open positions, and whoever scores least goes to the bench
+	if bt.GetSlotting() == d.SlottingPoints {
+		g.Reslot(bt, streamer, true)
+		return
+	}
+
 	// Find the matching positions for the player
 	matches := make([]string, 0, len(streamer.ValidPositions))
 	for _, pos := range streamer.ValidPositions {
@@ -76,9 +95,11 @@ func (g *Gene) SlotPlayer(bt *t.BaseTeam, streamer d.Player) {
 		}
 	}
 
-	// If there are no matches, add the streamer to the bench
+	// If there are no matches, see if moving the streamers already slotted around makes room, otherwise add the streamer to the bench
 	if len(matches) == 0 {
-		g.Bench.AddPlayer(streamer)
+		if !g.Reslot(bt, streamer, false) {
+			g.Bench.AddPlayer(streamer)
+		}
 		return
 	}
 
@@ -104,9 +125,61 @@ func (g *Gene) SlotPlayer(bt *t.BaseTeam, streamer d.Player) {
 
 
 
+// Function to try slotting a streamer by reslotting the streamers already in the gene's open positions. Unless bench_left_out is set
+// this only happens if everyone still gets a spot, otherwise the streamers on the bench who play that day compete for the spots too and
+// whoever doesn't get one is moved to the bench
+func (g *Gene) Reslot(bt *t.BaseTeam, streamer d.Player, bench_left_out bool) bool {
+
+	// Match the players to the open positions from most to least restrictive
+	var slots []string
+	players := []d.Player{streamer}
+	for _, pos := range bt.GetRosterTemplate().SlotOrder() {
+		if bt.UnusedPositions[g.Day][pos] {
+			slots = append(slots, pos)
+			if player, ok := g.Roster[pos]; ok && player.Name != "" {
+				players = append(players, player)
+			}
+		}
+	}
+	if bench_left_out {
+		for _, player := range g.Bench.Players {
+			if bt.CanPlay(player, g.Day) {
+				players = append(players, player)
+			}
+		}
+	}
+	lineup := bt.MatchPlayers(players, slots, g.Day)
+	if len(lineup) < len(players) && !bench_left_out {
+		return false
+	}
+
+	g.Roster = lineup
+	for _, pos := range slots {
+		_, taken := lineup[pos]
+		g.FreePositions[pos] = !taken
+	}
+	for _, player := range players {
+		switch on_bench := g.Bench.IsOnBench(player); {
+		case g.IsPlayerInRoster(player) && on_bench:
+			g.Bench.RemovePlayer(player)
+		case !g.IsPlayerInRoster(player) && !on_bench:
+			g.Bench.AddPlayer(player)
+		}
+	}
+	return true
+}
+
+
+
+
 // Function to find a valid free agent to add to the gene
 func (g *Gene) FindRandomFreeAgent(bt *t.BaseTeam, c *Chromosome, rng *rand.Rand, replaced d.Player) d.Player {
 
+	// A league can have nobody left to pick up, in which case there is no move to make
+	if len(bt.FreeAgents) == 0 {
+		return d.Player{}
+	}
+
 	for trials, cont := 0, true; trials < 25 && cont; trials++ {
 		index := rng.Intn(len(bt.FreeAgents))
 		free_agent := bt.FreeAgents[index]
@@ -119,8 +192,11 @@ func (g *Gene) FindRandomFreeAgent(bt *t.BaseTeam, c *Chromosome, rng *rand.Rand
 			}
 		}
 
-		// Check if the free agent is playing
-		if !d.ScheduleMap.IsPlaying(bt.Week, g.Day, free_agent.Team) || free_agent.Injured {
+		// Check if the free agent is playing and healthy, passing on players who are listed as questionable as often as they are expected to sit
+		if !bt.CanPlay(free_agent, g.Day) {
+			continue
+		}
+		if probability, ok := g.Availability[free_agent.Name]; ok && rng.Float64() >= probability {
 			continue
 		}
 
@@ -174,6 +250,29 @@ func (g *Gene) FindRandomFreeAgent(bt *t.BaseTeam, c *Chromosome, rng *rand.Rand
 
 
 
+// Function to set the gene's lineup outright, putting the rest of the streamers on the bench
+func (g *Gene) SetLineup(bt *t.BaseTeam, lineup map[string]d.Player, streamers []d.Player) {
+
+	g.Roster = make(map[string]d.Player)
+	g.FreePositions = make(map[string]bool)
+	g.Bench.Players = g.Bench.Players[:0]
+	for pos := range bt.UnusedPositions[g.Day] {
+		g.FreePositions[pos] = true
+	}
+	for pos, player := range lineup {
+		g.Roster[pos] = player
+		g.FreePositions[pos] = false
+	}
+	for _, streamer := range streamers {
+		if !g.IsPlayerInRoster(streamer) {
+			g.Bench.AddPlayer(streamer)
+		}
+	}
+}
+
+
+
+
 // Function to drop a player from the gene
 func (g *Gene) RemoveStreamer(streamer d.Player) {
 
@@ -248,6 +347,27 @@ func (g *Gene) IsPlayerInGene(player d.Player) bool {
 
 // ------------------------- Utils ------------------------- //
 
+// Function to get the points a player is projected to score on the gene's day, falling back to his season average
+func (g *Gene) ProjectedPoints(player d.Player) float64 {
+	if points, ok := g.Projections[player.Name]; ok {
+		return points
+	}
+	return player.AvgPoints
+}
+
+// Function to get the chance that a player plays on the gene's day
+func (g *Gene) PlayProbability(player d.Player) float64 {
+	if probability, ok := g.Availability[player.Name]; ok {
+		return probability
+	}
+	return 1
+}
+
+// Function to get the points a player is expected to score in the gene, weighted by the chance that he plays
+func (g *Gene) ExpectedPoints(player d.Player) float64 {
+	return g.PlayProbability(player) * g.ProjectedPoints(player)
+}
+
 // Function to get the number of streamers that are currently in the gene somewhere
 func (g *Gene) GetNumStreamers() int {
 	
@@ -261,6 +381,34 @@ func (g *Gene) GetNumStreamers() int {
 	return count
 }
 
+// Function to create a deep copy of the gene
+func (g *Gene) Copy() *Gene {
+	gene := &Gene{
+		Roster: make(map[string]d.Player, len(g.Roster)),
+		FreePositions: make(map[string]bool, len(g.FreePositions)),
+		NewPlayers: make([]d.Player, len(g.NewPlayers), cap(g.NewPlayers)),
+		DroppedPlayers: make([]d.Player, len(g.DroppedPlayers)),
+		Day: g.Day,
+		Acquisitions: g.Acquisitions,
+		Bench: u.Bench{Players: make([]d.Player, len(g.Bench.Players), cap(g.Bench.Players)), Projections: g.Bench.Projections},
+		Template: g.Template,
+		Availability: g.Availability,
+		Projections: g.Projections,
+	}
+
+	for pos, player := range g.Roster {
+		gene.Roster[pos] = player
+	}
+	for pos, free := range g.FreePositions {
+		gene.FreePositions[pos] = free
+	}
+	copy(gene.NewPlayers, g.NewPlayers)
+	copy(gene.DroppedPlayers, g.DroppedPlayers)
+	copy(gene.Bench.Players, g.Bench.Players)
+
+	return gene
+}
+
 // Function to slim down the gene to only the necessary information
 func (g *Gene) Slim() u.SlimGene {
 
@@ -304,7 +452,11 @@ func (g *Gene) IsPlayerInRoster(player d.Player) bool {
 // Function to print the gene
 func (g *Gene) Print() {
 	
-	order := []string{"PG", "SG", "SF", "PF", "C", "G", "F", "UT1", "UT2", "UT3"}
+	template := g.Template
+	if template == nil {
+		template = d.DefaultRosterTemplate()
+	}
+	order := template.StarterSlots()
 
 	for _, pos := range order {
 		if val, ok := g.FreePositions[pos]; ok && val {
@@ -1,11 +1,11 @@
 package population
 
 import (
+	"context"
 	"math"
 	"math/rand"
 	"sort"
 	"sync"
-	"time"
 	d "v2/data"
 	t "v2/team"
 	u "v2/utils"
@@ -15,54 +15,72 @@ import (
 type EvolutionManager struct {
 	Population 	   []*Chromosome
 	NumChromosomes int
+	Config 		     u.OptimizerConfig
+	Rng 			     *rand.Rand
+	Island 		     int
+	Generation     int
+	OnProgress     ProgressFunc
+	Fitness        FitnessFunc
 }
 
-// Function to create a new population
-func InitPopulation(bt *t.BaseTeam, size int) *EvolutionManager {
+// Function to create a new population. All randomness is derived from rng so that a fixed seed always produces the same population.
+// If ctx is cancelled part way through, the chromosomes that were finished are kept and the context's error is returned
+func InitPopulation(ctx context.Context, bt *t.BaseTeam, config u.OptimizerConfig, rng *rand.Rand) (*EvolutionManager, error) {
 
-	// Create a new population
-	ev := &EvolutionManager{Population: make([]*Chromosome, size), NumChromosomes: size}
+	// Create a new population with its own random number generator for future generations
+	size := config.PopulationSize
+	ev := &EvolutionManager{Population: make([]*Chromosome, size), NumChromosomes: size, Config: config, Rng: rand.New(rand.NewSource(rng.Int63())), Fitness: NewFitnessFunc(config)}
+
+	// Draw the seeds up front so that they don't depend on goroutine scheduling
+	seeds := make([]int64, size)
+	for i := range seeds {
+		seeds[i] = rng.Int63()
+	}
 
 	var wg sync.WaitGroup
-	ch := make(chan *Chromosome)
 
-	// Create [size] goroutines to generate chromosomes concurrently
+	// Create [size] goroutines to generate chromosomes concurrently, each writing to its own index
 	for i := 0; i < size; i++ {
 		wg.Add(1)
-		go func() {
+		go func(i int) {
 			defer wg.Done()
 
-			chromosome := InitChromosome(bt)
-
-			// Create random number generator
-			seed := time.Now().UnixNano() + int64(i)
-			rng := rand.New(rand.NewSource(seed))
+			// Don't start on new chromosomes once the context is done
+			if ctx.Err() != nil {
+				return
+			}
 
-			chromosome.Populate(bt, rng)
-			chromosome.ScoreFitness()
+			chromosome := InitChromosome(bt)
+			chromosome.Populate(bt, rand.New(rand.NewSource(seeds[i])))
+			ev.ScoreFitness(chromosome)
 			
-			ch <- chromosome
-		}()
+			ev.Population[i] = chromosome
+		}(i)
 	}
+	wg.Wait()
 
-	// Wait for all goroutines to finish and collect the chromosomes
-	go func() {
-		wg.Wait()
-		close(ch)
-	}()
-
-	// Collect the chromosomes from the channel
-	i := 0
-	for chromosome := range ch {
-		ev.Population[i] = chromosome
-		i++
+	// Drop the chromosomes that never got created
+	if err := ctx.Err(); err != nil {
+		finished := make([]*Chromosome, 0, size)
+		for _, chromosome := range ev.Population {
+			if chromosome != nil {
+				finished = append(finished, chromosome)
+			}
+		}
+		ev.Population = finished
+		ev.NumChromosomes = len(finished)
+		return ev, err
 	}
 
-	return ev
+	return ev, nil
 }
 
-// Function to evlove the population using the genetic algorithm
-func (ev *EvolutionManager) Evolve(bt *t.BaseTeam) {
+// Function to evlove the population using the genetic algorithm. If ctx is cancelled part way through, the generation is abandoned and the previous population is kept
+func (ev *EvolutionManager) Evolve(ctx context.Context, bt *t.BaseTeam) error {
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 
 	// Selection: assign cumulative probabilities to the chromosomes making more fit chromosomes more likely to be selected
 	ev.SortByFitness()
@@ -76,9 +94,13 @@ func (ev *EvolutionManager) Evolve(bt *t.BaseTeam) {
 	// Generate the rest of the chromosomes
 	for i := 0; i < ev.NumChromosomes-1; i++ {
 
-		// Create random seed
-		seed := rand.NewSource(time.Now().UnixNano() + int64(i))
-		rng := rand.New(seed)
+		// Stop if the run has been cancelled or is out of time
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		// Create random number generator from the population's generator
+		rng := rand.New(rand.NewSource(ev.Rng.Int63()))
 
 		// Selection: select two parents
 		parent1 := ev.SelectParent(1, rng)
@@ -88,23 +110,59 @@ func (ev *EvolutionManager) Evolve(bt *t.BaseTeam) {
 		child := ev.Crossover(bt, parent1, parent2, rng)
 		
 		// Mutation: mutate the child
-		prob_of_mutation := 0.20
-		child.Mutate(bt, prob_of_mutation, rng)
-		child.ScoreFitness()
+		child.Mutate(bt, ev.Config.MutationRate, rng)
+		ev.ScoreFitness(child)
+
+		// In hard mode, a child that breaks the acquisition limit is rejected in favor of its first parent
+		if child.Limit.Hard && !child.IsFeasible() {
+			child = parent1.Copy()
+		}
 
 		next_generation[i] = child
 	}
 
 	// Replace the old population with the new population
 	ev.Population = next_generation
+	ev.Generation++
 
+	ev.ReportProgress()
+	return nil
+}
+
+// Function to score a chromosome with the population's fitness function, falling back to the default if it doesn't have one
+func (ev *EvolutionManager) ScoreFitness(c *Chromosome) {
+	if ev.Fitness == nil {
+		c.ScoreFitness()
+		return
+	}
+	c.FitnessScore = ev.Fitness.Score(c)
+}
+
+// Function to swap in a different fitness function and rescore the population with it
+func (ev *EvolutionManager) SetFitness(fitness FitnessFunc) {
+	ev.Fitness = fitness
+	for _, chromosome := range ev.Population {
+		ev.ScoreFitness(chromosome)
+	}
+}
+
+// Function to get the fittest chromosome that stays within the acquisition limit, or nil if there is none
+func (ev *EvolutionManager) BestValid() *Chromosome {
+
+	ev.SortByFitness()
+	for i := ev.NumChromosomes - 1; i >= 0; i-- {
+		if ev.Population[i].IsFeasible() {
+			return ev.Population[i]
+		}
+	}
+	return nil
 }
 
 // Function to assign cumulative probabilities to the chromosomes
 func (ev *EvolutionManager) AssignCumProbs() {
 
 	GetProbability := func(x int) float64 {
-		return math.Pow(float64(x) / float64(ev.NumChromosomes), 1.5) + 0.02
+		return math.Pow(float64(x) / float64(ev.NumChromosomes), ev.Config.RouletteExponent) + ev.Config.RouletteFloor
 	}
 
 	cum_prob := GetProbability(0)
@@ -131,7 +189,7 @@ func (ev *EvolutionManager) SelectParent(num int, rng *rand.Rand) *Chromosome {
 	switch num {
 	case 1:
 		// Select a parent using roulette wheel selection
-		rand_num := rand.Float64() * ev.Population[ev.NumChromosomes - 1].CumProbTracker
+		rand_num := rng.Float64() * ev.Population[ev.NumChromosomes - 1].CumProbTracker
 
 		for _, chromosome := range ev.Population {
 			if chromosome.CumProbTracker >= rand_num {
@@ -140,18 +198,19 @@ func (ev *EvolutionManager) SelectParent(num int, rng *rand.Rand) *Chromosome {
 		}
 	case 2:
 		// Select a parent using tournament selection
-		tournament := make([][5]*Chromosome, 3)
+		tournament := make([][]*Chromosome, ev.Config.TournamentCount)
 
-		for i := 0; i < 3; i++ {
-			for j := 0; j < 5; j++ {
+		for i := 0; i < ev.Config.TournamentCount; i++ {
+			tournament[i] = make([]*Chromosome, ev.Config.TournamentSize)
+			for j := 0; j < ev.Config.TournamentSize; j++ {
 				rand_num := rng.Intn(ev.NumChromosomes)
 				tournament[i][j] = ev.Population[rand_num]
 			}
-			sort.Slice(tournament[i][:], func(k, l int) bool {
+			sort.Slice(tournament[i], func(k, l int) bool {
 				return tournament[i][k].FitnessScore < tournament[i][l].FitnessScore
 			})
 		}
-		return tournament[rng.Intn(3)][1]
+		return tournament[rng.Intn(ev.Config.TournamentCount)][ev.Config.TournamentPick]
 	}
 
 	return ev.Population[ev.NumChromosomes - 1]
@@ -174,14 +233,19 @@ func (ev *EvolutionManager) Crossover(bt *t.BaseTeam, parent1, parent2 *Chromoso
 
 		// Create a copy of the current streamers
 		old_streamers := make(map[string]d.Player)
+		old_streamer_order := make([]d.Player, len(child.CurStreamers))
+		copy(old_streamer_order, child.CurStreamers)
 		for _, player := range child.CurStreamers {
 			old_streamers[player.Name] = player
 		}
 
+		// Make room for any players coming back from IR today
+		child.MakeForcedDrops(bt, i)
+
 		ev.MixGenes(bt, child, parent1.Genes[i], parent2.Genes[i], rng)
 
-		// Go through the old streamers and find the ones that were dropped
-		for _, old_player := range old_streamers {
+		// Go through the old streamers in their original order and find the ones that were dropped
+		for _, old_player := range old_streamer_order {
 			if !u.SliceContainsPlayer(child.CurStreamers, &old_player) {
 				child.DroppedPlayers[old_player.Name] = d.DroppedPlayer{Player: old_player, Countdown: 3}
 				gene.DroppedPlayers = append(gene.DroppedPlayers, old_player)
@@ -205,6 +269,9 @@ func (ev *EvolutionManager) Crossover(bt *t.BaseTeam, parent1, parent2 *Chromoso
 // Function to mix the genes of two parent chromosomes
 func (ev *EvolutionManager) MixGenes(bt *t.BaseTeam, child *Chromosome, parent1, parent2 *Gene, rng *rand.Rand) {
 
+	// Genes are indexed from the first day being optimized
+	index := parent1.Day - child.StartDay
+
 	// Create a list of all the new players in the parent genes
 	new_players := make([]d.Player, 0, len(parent1.NewPlayers) + len(parent2.NewPlayers))
 	new_players = append(new_players, parent1.NewPlayers...)
@@ -226,10 +293,15 @@ func (ev *EvolutionManager) MixGenes(bt *t.BaseTeam, child *Chromosome, parent1,
 		num_players = 1
 	}
 
+	// In hard mode, never make more acquisitions than the league allows
+	if child.Limit.Hard {
+		num_players = min(num_players, child.AcquisitionAllowance(index))
+	}
+
 	// Add the new players to the child
 	for i := 0; i < num_players; i++ {
-		if p, ok := child.DroppedPlayers[new_players[i].Name]; (!ok || (p.Player.Name != "" && p.Countdown == 0)) && !child.Genes[parent1.Day].IsPlayerInGene(new_players[i]) {
-			child.InsertFreeAgent(bt, parent1.Day, new_players[i])
+		if p, ok := child.DroppedPlayers[new_players[i].Name]; (!ok || (p.Player.Name != "" && p.Countdown == 0)) && !child.Genes[index].IsPlayerInGene(new_players[i]) {
+			child.InsertFreeAgent(bt, index, new_players[i])
 			// child.Genes[parent1.Day].NewPlayers = append(child.Genes[parent1.Day].NewPlayers, new_players[i])
 			// child.Genes[parent1.Day].Acquisitions++
 			// child.TotalAcquisitions++
@@ -0,0 +1,44 @@
+package population
+
+import (
+	"sort"
+)
+
+// Island index used when reporting on the population that the islands were merged into
+const MergedIsland = -1
+
+// Struct for a snapshot of how a population is doing after a generation
+type Progress struct {
+	Island           int     `json:"island"`
+	Generation       int     `json:"generation"`
+	BestFitness      float64 `json:"best_fitness"`
+	MedianFitness    float64 `json:"median_fitness"`
+	BestAcquisitions int     `json:"best_acquisitions"`
+}
+
+// Callback that is invoked after every generation. Islands evolve concurrently, so it has to be safe to call from several goroutines
+type ProgressFunc func(Progress)
+
+// Function to report the current state of the population to the progress callback if there is one
+func (ev *EvolutionManager) ReportProgress() {
+
+	if ev.OnProgress == nil || ev.NumChromosomes == 0 {
+		return
+	}
+
+	// Sort a copy of the population so that reporting doesn't reorder the population itself
+	sorted := make([]*Chromosome, len(ev.Population))
+	copy(sorted, ev.Population)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].FitnessScore < sorted[j].FitnessScore
+	})
+	best := sorted[len(sorted)-1]
+
+	ev.OnProgress(Progress{
+		Island:           ev.Island,
+		Generation:       ev.Generation,
+		BestFitness:      best.FitnessScore,
+		MedianFitness:    sorted[len(sorted)/2].FitnessScore,
+		BestAcquisitions: best.TotalAcquisitions,
+	})
+}
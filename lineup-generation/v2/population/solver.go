@@ -0,0 +1,452 @@
+package population
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	d "v2/data"
+	t "v2/team"
+)
+
+// Struct for how much the exact solver is allowed to search. Every plan for a day that is tried counts as a node, and MaxNodes of 0 means
+// the search only stops when it's done or ctx is cancelled. Each day only the MaxCandidates players expected to score the most that day
+// are considered for pickup and at most MaxMoves moves are made, with 0 meaning DefaultSolverCandidates and DefaultSolverMoves
+type SolverOptions struct {
+	MaxNodes      int
+	MaxCandidates int
+	MaxMoves      int
+}
+
+// Limits on the moves the exact solver tries each day, so that a large free agent pool can't make a single day take forever
+const (
+	DefaultSolverCandidates = 15
+	DefaultSolverMoves      = 3
+)
+
+// Number of daily lineups the exact solver remembers before starting over, so that a long search can't run out of memory
+const SolverMemoSize = 1 << 16
+
+// Struct for what the exact solver found. Value is the expected points the streamers score under the best plan found and Bound is an
+// upper bound on the best plan there is, so the two are equal when the search ran to the end. Stopped says the search ran out of nodes
+// or was cancelled, and Exact that it neither stopped nor left any moves out
+type SolverResult struct {
+	Chromosome *Chromosome
+	Value      float64
+	Bound      float64
+	Exact      bool
+	Stopped    bool
+	Nodes      int
+}
+
+// Function to get how far the plan found could be from the best one, as a fraction of the bound
+func (r SolverResult) Gap() float64 {
+	if r.Bound <= 0 {
+		return 0
+	}
+	return (r.Bound - r.Value) / r.Bound
+}
+
+// Number of days after being dropped that a player can be picked up again, matching the countdown the genetic algorithm gives dropped players
+const WaiverDays = 3
+
+// Struct for the moves made on a day and the streamers held afterwards
+type dayPlan struct {
+	Adds   []d.Player
+	Drops  []d.Player
+	Roster []d.Player
+}
+
+// Struct for the state of a search
+type solver struct {
+	bt         *t.BaseTeam
+	ctx        context.Context
+	options    SolverOptions
+	limit      d.AcquisitionLimit
+	num_days   int
+	relaxed    []float64
+	day_values map[string]float64
+	best_value float64
+	best_plan  []dayPlan
+	open_bound float64
+	nodes      int
+	stopped    bool
+	capped     bool
+}
+
+// Function to find the plan of acquisitions that gets the most expected points out of the streamers without breaking the acquisition
+// limit, using branch and bound over the days of the week. The model is the one the genetic algorithm works with: each move swaps a
+// streamer for a free agent who plays that day, a dropped player can only be picked up again WaiverDays after he was dropped, and
+// players coming back from IR force drops. Every lineup is slotted optimally, so when no moves are left out the result is an upper bound
+// on anything the genetic algorithm can find. If the search runs out of nodes, ctx is cancelled or a day has more moves than the options
+// allow, the best plan so far is returned along with a bound on how much better the best plan could be
+func Solve(ctx context.Context, bt *t.BaseTeam, options SolverOptions) SolverResult {
+
+	if options.MaxCandidates <= 0 {
+		options.MaxCandidates = DefaultSolverCandidates
+	}
+	if options.MaxMoves <= 0 {
+		options.MaxMoves = DefaultSolverMoves
+	}
+
+	base := InitChromosome(bt)
+	s := &solver{
+		bt:         bt,
+		ctx:        ctx,
+		options:    options,
+		limit:      base.Limit,
+		num_days:   len(base.Genes),
+		day_values: make(map[string]float64),
+		open_bound: math.Inf(-1),
+	}
+	s.relaxed = s.relaxedBounds()
+
+	roster := make([]d.Player, len(bt.StreamablePlayers))
+	copy(roster, bt.StreamablePlayers)
+	s.holdRoster(roster)
+	s.search(0, roster, make(map[string]int), make([]int, s.num_days), 0, nil)
+
+	result := SolverResult{Value: s.best_value, Bound: math.Max(s.best_value, s.open_bound), Exact: !s.stopped && !s.capped, Stopped: s.stopped, Nodes: s.nodes}
+	result.Chromosome = s.buildChromosome(base)
+	return result
+}
+
+// Function to search the plans from gene index i onwards given the streamers held going into the day and the gene index each dropped
+// player was last dropped on
+func (s *solver) search(i int, roster []d.Player, dropped map[string]int, daily []int, value float64, plan []dayPlan) {
+
+	if i == s.num_days {
+		if value > s.best_value {
+			s.best_value = value
+			s.best_plan = append([]dayPlan(nil), plan...)
+		}
+		return
+	}
+
+	// Prune plans that can't beat the best one found so far
+	bound := value + s.bound(i, roster, daily)
+	if bound <= s.best_value+1e-9 {
+		return
+	}
+
+	// Once out of nodes or time, remember how good the rest of this branch could have been
+	if s.stopped || s.ctx.Err() != nil {
+		s.stop(bound)
+		return
+	}
+
+	day := s.bt.StartDay + i
+	forced := min(s.bt.ForcedDrops[day], len(roster))
+	allowance := s.limit.Allowance(s.bt.StartDay, daily, s.bt.AcquisitionsUsed, i)
+
+	// Players worth adding today are the ones off the roster who play today and aren't on waivers, which includes streamers who were
+	// dropped long enough ago. Only the ones expected to score the most today are tried
+	var candidates []d.Player
+	for _, player := range append(append([]d.Player(nil), s.bt.FreeAgents...), s.bt.StreamablePlayers...) {
+		if drop_day, ok := dropped[player.Name]; ok && i < drop_day+WaiverDays {
+			continue
+		}
+		if !containsPlayer(roster, player) && !containsPlayer(candidates, player) && s.bt.CanPlay(player, day) {
+			candidates = append(candidates, player)
+		}
+	}
+	sort.SliceStable(candidates, func(a, b int) bool {
+		return s.bt.ExpectedPoints(candidates[a], day) > s.bt.ExpectedPoints(candidates[b], day)
+	})
+	max_moves := min(allowance, len(roster)-forced, len(candidates))
+	if max_moves > 0 && (len(candidates) > s.options.MaxCandidates || max_moves > s.options.MaxMoves) {
+		s.capped = true
+		s.open_bound = math.Max(s.open_bound, bound)
+		candidates = candidates[:min(len(candidates), s.options.MaxCandidates)]
+		max_moves = min(max_moves, s.options.MaxMoves)
+	}
+
+	// Drop the streamers expected to score the least today first
+	drop_order := append([]d.Player(nil), roster...)
+	sort.SliceStable(drop_order, func(a, b int) bool {
+		return s.bt.ExpectedPoints(drop_order[a], day) < s.bt.ExpectedPoints(drop_order[b], day)
+	})
+
+	// Try the moves one at a time rather than listing them all up front, most moves first since streaming more usually scores more,
+	// counting every one against the node limit
+	for moves := max_moves; moves >= 0 && !s.stopped; moves-- {
+		eachCombination(drop_order, forced+moves, func(drops []d.Player) bool {
+			return eachCombination(candidates, moves, func(adds []d.Player) bool {
+				s.nodes++
+				if s.options.MaxNodes > 0 && s.nodes > s.options.MaxNodes || s.ctx.Err() != nil {
+					s.stop(bound)
+					return false
+				}
+
+				next := make([]d.Player, 0, len(roster)-forced)
+				for _, player := range roster {
+					if !containsPlayer(drops, player) {
+						next = append(next, player)
+					}
+				}
+				next = append(next, adds...)
+				day_plan := dayPlan{Adds: append([]d.Player(nil), adds...), Drops: append([]d.Player(nil), drops...), Roster: next}
+
+				previous := make(map[string]int, len(drops))
+				for _, player := range drops {
+					if drop_day, ok := dropped[player.Name]; ok {
+						previous[player.Name] = drop_day
+					}
+					dropped[player.Name] = i
+				}
+				daily[i] = len(adds)
+
+				s.search(i+1, next, dropped, daily, value+s.dayValue(day, next), append(plan, day_plan))
+
+				daily[i] = 0
+				for _, player := range drops {
+					if drop_day, ok := previous[player.Name]; ok {
+						dropped[player.Name] = drop_day
+					} else {
+						delete(dropped, player.Name)
+					}
+				}
+				return !s.stopped
+			})
+		})
+	}
+
+	// A search stopped partway through leaves the rest of the day's moves untried
+	if s.stopped {
+		s.open_bound = math.Max(s.open_bound, bound)
+	}
+}
+
+// Function to stop the search, remembering the bound of the branch it stopped in
+func (s *solver) stop(bound float64) {
+	s.stopped = true
+	s.open_bound = math.Max(s.open_bound, bound)
+}
+
+// Function to start the search off with the plan of making no moves beyond the drops that players coming back from IR force, so that
+// there is always a plan to return however soon the search is stopped
+func (s *solver) holdRoster(roster []d.Player) {
+
+	value := 0.0
+	plan := make([]dayPlan, s.num_days)
+	for i := range plan {
+		day := s.bt.StartDay + i
+
+		// Drop the streamers with the lowest averages, as the genetic algorithm does
+		forced := min(s.bt.ForcedDrops[day], len(roster))
+		sorted := append([]d.Player(nil), roster...)
+		sort.SliceStable(sorted, func(a, b int) bool {
+			return sorted[a].AvgPoints < sorted[b].AvgPoints
+		})
+		drops := sorted[:forced]
+		var next []d.Player
+		for _, player := range roster {
+			if !containsPlayer(drops, player) {
+				next = append(next, player)
+			}
+		}
+
+		roster = next
+		plan[i] = dayPlan{Drops: drops, Roster: roster}
+		value += s.dayValue(day, roster)
+	}
+
+	s.best_value, s.best_plan = value, plan
+}
+
+// Function to bound the points that can still be scored from gene index i onwards. Once no more moves can be made the streamers are
+// locked in and their points are known exactly, otherwise each day is bounded by its best possible streamers
+func (s *solver) bound(i int, roster []d.Player, daily []int) float64 {
+
+	locked := s.limit.Period == d.PeriodMatchup && s.limit.Allowance(s.bt.StartDay, daily, s.bt.AcquisitionsUsed, i) == 0
+	for j := i; j < s.num_days && locked; j++ {
+		locked = s.bt.ForcedDrops[s.bt.StartDay+j] == 0
+	}
+	if !locked {
+		return s.relaxed[i]
+	}
+
+	total := 0.0
+	for j := i; j < s.num_days; j++ {
+		total += s.dayValue(s.bt.StartDay+j, roster)
+	}
+	return math.Min(total, s.relaxed[i])
+}
+
+// Function to work out, for each gene index, a bound on the points that can be scored from that day onwards ignoring the acquisition
+// limit: each day, the best players who could be streamed fill as many of the open positions as there are streamers
+func (s *solver) relaxedBounds() []float64 {
+
+	pool := append(append([]d.Player(nil), s.bt.StreamablePlayers...), s.bt.FreeAgents...)
+	roster_size := len(s.bt.StreamablePlayers)
+
+	bounds := make([]float64, s.num_days+1)
+	per_day := make([]float64, s.num_days)
+	for i := 0; i < s.num_days; i++ {
+		day := s.bt.StartDay + i
+		roster_size = max(roster_size-s.bt.ForcedDrops[day], 0)
+
+		var points []float64
+		for _, player := range pool {
+			if s.bt.CanPlay(player, day) && s.fitsAnywhere(day, player) {
+				points = append(points, s.bt.ExpectedPoints(player, day))
+			}
+		}
+		sort.Sort(sort.Reverse(sort.Float64Slice(points)))
+		for j := 0; j < min(roster_size, len(s.bt.UnusedPositions[day]), len(points)); j++ {
+			per_day[i] += points[j]
+		}
+	}
+	for i := s.num_days - 1; i >= 0; i-- {
+		bounds[i] = bounds[i+1] + per_day[i]
+	}
+	return bounds
+}
+
+// Function to check if a player can fill any of the open positions on a day
+func (s *solver) fitsAnywhere(day int, player d.Player) bool {
+	for _, pos := range player.ValidPositions {
+		if s.bt.UnusedPositions[day][pos] {
+			return true
+		}
+	}
+	return false
+}
+
+// Function to get the most expected points a set of streamers can score on a day, remembering the answer for next time
+func (s *solver) dayValue(day int, roster []d.Player) float64 {
+
+	names := make([]string, len(roster))
+	for i, player := range roster {
+		names[i] = player.Name
+	}
+	sort.Strings(names)
+	key := strconv.Itoa(day) + ":" + strings.Join(names, "|")
+	if value, ok := s.day_values[key]; ok {
+		return value
+	}
+
+	value, _ := BestLineup(s.bt, day, roster)
+	if len(s.day_values) >= SolverMemoSize {
+		s.day_values = make(map[string]float64)
+	}
+	s.day_values[key] = value
+	return value
+}
+
+// Function to find the lineup of streamers that scores the most expected points in the positions left open on a day, by trying every
+// way of slotting the players who play that day
+func BestLineup(bt *t.BaseTeam, day int, roster []d.Player) (float64, map[string]d.Player) {
+
+	var playing []d.Player
+	for _, player := range roster {
+		if bt.CanPlay(player, day) {
+			playing = append(playing, player)
+		}
+	}
+	sort.SliceStable(playing, func(i, j int) bool {
+		return bt.ExpectedPoints(playing[i], day) > bt.ExpectedPoints(playing[j], day)
+	})
+
+	// Points still available from each player onwards, for pruning
+	remaining := make([]float64, len(playing)+1)
+	for i := len(playing) - 1; i >= 0; i-- {
+		remaining[i] = remaining[i+1] + bt.ExpectedPoints(playing[i], day)
+	}
+
+	best_value := 0.0
+	best_lineup := make(map[string]d.Player)
+	lineup := make(map[string]d.Player)
+
+	var fit func(index int, value float64)
+	fit = func(index int, value float64) {
+		if value > best_value {
+			best_value = value
+			best_lineup = make(map[string]d.Player, len(lineup))
+			for pos, player := range lineup {
+				best_lineup[pos] = player
+			}
+		}
+		if index == len(playing) || value+remaining[index] <= best_value {
+			return
+		}
+
+		player := playing[index]
+		for _, pos := range player.ValidPositions {
+			if _, taken := lineup[pos]; bt.UnusedPositions[day][pos] && !taken {
+				lineup[pos] = player
+				fit(index+1, value+bt.ExpectedPoints(player, day))
+				delete(lineup, pos)
+			}
+		}
+		fit(index+1, value)
+	}
+	fit(0, 0)
+
+	return best_value, best_lineup
+}
+
+// Function to turn the best plan into a chromosome so that it can be returned like any other lineup
+func (s *solver) buildChromosome(c *Chromosome) *Chromosome {
+
+	for i, gene := range c.Genes {
+		if i >= len(s.best_plan) {
+			break
+		}
+		plan := s.best_plan[i]
+
+		_, lineup := BestLineup(s.bt, gene.Day, plan.Roster)
+		gene.SetLineup(s.bt, lineup, plan.Roster)
+		gene.NewPlayers = append(gene.NewPlayers, plan.Adds...)
+		gene.DroppedPlayers = append(gene.DroppedPlayers, plan.Drops...)
+		gene.Acquisitions = len(plan.Adds)
+		c.TotalAcquisitions += len(plan.Adds)
+		c.CurStreamers = plan.Roster
+		for _, player := range plan.Adds {
+			delete(c.DroppedPlayers, player.Name)
+		}
+		for _, player := range plan.Drops {
+			c.DroppedPlayers[player.Name] = d.DroppedPlayer{Player: player, Countdown: WaiverDays}
+		}
+	}
+
+	c.ScoreFitness()
+	return c
+}
+
+// Function to check if a player is in a slice by name
+func containsPlayer(players []d.Player, player d.Player) bool {
+	for _, p := range players {
+		if p.Name == player.Name {
+			return true
+		}
+	}
+	return false
+}
+
+// Function to go through every way of choosing k players, keeping the order they came in, until yield returns false. The slice passed
+// to yield is reused, so it has to be copied to be kept. Returns false if it was stopped early
+func eachCombination(players []d.Player, k int, yield func([]d.Player) bool) bool {
+
+	if k > len(players) {
+		return true
+	}
+
+	chosen := make([]d.Player, 0, k)
+	var choose func(start int) bool
+	choose = func(start int) bool {
+		if len(chosen) == k {
+			return yield(chosen)
+		}
+		for i := start; i <= len(players)-(k-len(chosen)); i++ {
+			chosen = append(chosen, players[i])
+			if !choose(i + 1) {
+				return false
+			}
+			chosen = chosen[:len(chosen)-1]
+		}
+		return true
+	}
+	return choose(0)
+}
@@ -0,0 +1,22 @@
+package resources
+
+import (
+	"path/filepath"
+	"runtime"
+)
+
+// Function to get the directory this package lives in, so that the mock data can be found no matter where the caller is run from
+func dir() string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Dir(file)
+}
+
+// Function to get the path of the mock roster
+func MockRosterPath() string {
+	return filepath.Join(dir(), "mock_roster.json")
+}
+
+// Function to get the path of the mock free agents
+func MockFreeAgentsPath() string {
+	return filepath.Join(dir(), "mock_freeagents.json")
+}
@@ -0,0 +1,114 @@
+package schedule
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+	d "v2/data"
+)
+
+// Function to build the season schedule the optimizer reads from the NBA's schedule feed and the league's calendar.
+// Matchups are numbered from 1 and each team's games are keyed by the number of days since the start of the matchup
+func Build(nba *NBASchedule, calendar *Calendar) (d.SeasonSchedule, error) {
+
+	season_start, err := time.Parse(DateLayout, calendar.SeasonStart)
+	if err != nil {
+		return d.SeasonSchedule{}, fmt.Errorf("season start: %w", err)
+	}
+
+	// Index the feed's weeks by number
+	type span struct{ start, end time.Time }
+	weeks := make(map[int]span)
+	week_numbers := []int{}
+	for _, week := range nba.LeagueSchedule.Weeks {
+		start, err := parseDate(NBAWeekLayout, week.StartDate)
+		if err != nil {
+			return d.SeasonSchedule{}, fmt.Errorf("week %d start: %w", week.WeekNumber, err)
+		}
+		end, err := parseDate(NBAWeekLayout, week.EndDate)
+		if err != nil {
+			return d.SeasonSchedule{}, fmt.Errorf("week %d end: %w", week.WeekNumber, err)
+		}
+		weeks[week.WeekNumber] = span{start, end}
+		week_numbers = append(week_numbers, week.WeekNumber)
+	}
+	sort.Ints(week_numbers)
+
+	matchups, err := calendar.Matchups(week_numbers)
+	if err != nil {
+		return d.SeasonSchedule{}, err
+	}
+
+	// Work out the dates of each matchup
+	schedule := d.SeasonSchedule{Schedule: make(map[string]d.WeekSchedule)}
+	starts := make([]time.Time, len(matchups))
+	ends := make([]time.Time, len(matchups))
+	for i, matchup := range matchups {
+		first, ok := weeks[matchup[0]]
+		if !ok {
+			return d.SeasonSchedule{}, fmt.Errorf("week %d is not in the NBA schedule", matchup[0])
+		}
+		last, ok := weeks[matchup[len(matchup)-1]]
+		if !ok {
+			return d.SeasonSchedule{}, fmt.Errorf("week %d is not in the NBA schedule", matchup[len(matchup)-1])
+		}
+
+		// The first matchup starts on opening night rather than with the feed's preseason
+		starts[i], ends[i] = first.start, last.end
+		if starts[i].Before(season_start) {
+			starts[i] = season_start
+		}
+		if ends[i].Before(starts[i]) {
+			return d.SeasonSchedule{}, fmt.Errorf("matchup %d ends before it starts", i+1)
+		}
+
+		schedule.Schedule[strconv.Itoa(i+1)] = d.WeekSchedule{
+			StartDate:     starts[i].Format(DateLayout),
+			EndDate:       ends[i].Format(DateLayout),
+			GameSpan:      int(ends[i].Sub(starts[i]).Hours() / 24),
+			TeamSchedules: make(map[string]map[string]bool),
+		}
+	}
+
+	// Slot every game into the matchup it falls in, ignoring the preseason and anything after the last matchup
+	for _, game_date := range nba.LeagueSchedule.GameDates {
+		date, err := parseDate(NBAGameLayout, game_date.GameDate)
+		if err != nil {
+			return d.SeasonSchedule{}, fmt.Errorf("game date: %w", err)
+		}
+
+		index := sort.Search(len(ends), func(i int) bool { return !ends[i].Before(date) })
+		if index == len(ends) || date.Before(starts[index]) {
+			continue
+		}
+
+		week := schedule.Schedule[strconv.Itoa(index+1)]
+		day := strconv.Itoa(int(date.Sub(starts[index]).Hours() / 24))
+		for _, game := range game_date.Games {
+			for _, team := range []string{game.HomeTeam.TeamTricode, game.AwayTeam.TeamTricode} {
+				if team == "" {
+					continue
+				}
+				if week.TeamSchedules[team] == nil {
+					week.TeamSchedules[team] = make(map[string]bool)
+				}
+				week.TeamSchedules[team][day] = true
+			}
+		}
+	}
+
+	return schedule, nil
+}
+
+// Function to write a season schedule in the format data.LoadSchedule reads
+func Write(path string, schedule d.SeasonSchedule) error {
+
+	bytes, err := json.MarshalIndent(schedule, "", "    ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(bytes, '\n'), 0644)
+}
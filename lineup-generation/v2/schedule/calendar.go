@@ -0,0 +1,94 @@
+package schedule
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	d "v2/data"
+)
+
+// Layout of the dates in calendars, the same as in the schedule files the optimizer reads
+const DateLayout = d.DateLayout
+
+// Struct for how a fantasy league lays its matchups over the NBA's weeks. Weeks are referred to by the feed's weekNumber.
+//
+// Every NBA week from the start of the season up to the first playoff week is its own matchup, except for the All-Star
+// weeks which are combined into one. Each playoff matchup is made up of the listed weeks, and nothing after the last one is kept
+type Calendar struct {
+	SeasonStart     string  `json:"season_start"`
+	AllStarWeeks    []int   `json:"all_star_weeks"`
+	PlayoffMatchups [][]int `json:"playoff_matchups"`
+}
+
+// Function to load a calendar from a JSON file
+func LoadCalendar(path string) (*Calendar, error) {
+
+	bytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var calendar Calendar
+	if err := json.Unmarshal(bytes, &calendar); err != nil {
+		return nil, fmt.Errorf("decoding %s: %w", path, err)
+	}
+	return &calendar, nil
+}
+
+// Function to group the NBA's week numbers into matchups, in order
+func (c *Calendar) Matchups(weeks []int) ([][]int, error) {
+
+	if err := consecutive(c.AllStarWeeks); err != nil {
+		return nil, fmt.Errorf("all-star weeks: %w", err)
+	}
+	for _, matchup := range c.PlayoffMatchups {
+		if len(matchup) == 0 {
+			return nil, fmt.Errorf("playoff matchup has no weeks")
+		}
+		if err := consecutive(matchup); err != nil {
+			return nil, fmt.Errorf("playoff matchup: %w", err)
+		}
+	}
+
+	// The regular season runs until the first playoff week
+	playoffs_start := -1
+	if len(c.PlayoffMatchups) > 0 {
+		playoffs_start = c.PlayoffMatchups[0][0]
+	}
+
+	matchups := [][]int{}
+	for _, week := range weeks {
+		if playoffs_start != -1 && week >= playoffs_start {
+			break
+		}
+
+		// Fold the rest of the All-Star weeks into the matchup started by the first one
+		if len(c.AllStarWeeks) > 0 && week > c.AllStarWeeks[0] && week <= c.AllStarWeeks[len(c.AllStarWeeks)-1] {
+			matchups[len(matchups)-1] = append(matchups[len(matchups)-1], week)
+			continue
+		}
+		matchups = append(matchups, []int{week})
+	}
+
+	// Playoff matchups have to follow straight on from the regular season
+	next := playoffs_start
+	for _, matchup := range c.PlayoffMatchups {
+		if matchup[0] != next {
+			return nil, fmt.Errorf("playoff matchup starting with week %d does not follow week %d", matchup[0], next-1)
+		}
+		matchups = append(matchups, matchup)
+		next = matchup[len(matchup)-1] + 1
+	}
+
+	return matchups, nil
+}
+
+// Function to check that a list of week numbers has no gaps
+func consecutive(weeks []int) error {
+	for i := 1; i < len(weeks); i++ {
+		if weeks[i] != weeks[i-1]+1 {
+			return fmt.Errorf("weeks %v are not consecutive", weeks)
+		}
+	}
+	return nil
+}
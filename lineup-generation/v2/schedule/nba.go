@@ -0,0 +1,67 @@
+package schedule
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// Layouts of the dates in the NBA's scheduleLeagueV2 feed
+const (
+	NBAWeekLayout = time.RFC3339
+	NBAGameLayout = "01/02/2006 15:04:05"
+)
+
+// Struct for the parts of the NBA's scheduleLeagueV2 feed (https://cdn.nba.com/static/json/staticData/scheduleLeagueV2.json) that the builder needs
+type NBASchedule struct {
+	LeagueSchedule struct {
+		SeasonYear string     `json:"seasonYear"`
+		Weeks      []NBAWeek  `json:"weeks"`
+		GameDates  []GameDate `json:"gameDates"`
+	} `json:"leagueSchedule"`
+}
+
+type NBAWeek struct {
+	WeekNumber int    `json:"weekNumber"`
+	StartDate  string `json:"startDate"`
+	EndDate    string `json:"endDate"`
+}
+
+type GameDate struct {
+	GameDate string `json:"gameDate"`
+	Games    []Game `json:"games"`
+}
+
+type Game struct {
+	HomeTeam GameTeam `json:"homeTeam"`
+	AwayTeam GameTeam `json:"awayTeam"`
+}
+
+type GameTeam struct {
+	TeamTricode string `json:"teamTricode"`
+}
+
+// Function to load the NBA's schedule feed from a local file
+func LoadNBASchedule(path string) (*NBASchedule, error) {
+
+	bytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var nba NBASchedule
+	if err := json.Unmarshal(bytes, &nba); err != nil {
+		return nil, fmt.Errorf("decoding %s: %w", path, err)
+	}
+	return &nba, nil
+}
+
+// Function to parse a date from the feed, dropping the time of day
+func parseDate(layout string, value string) (time.Time, error) {
+	parsed, err := time.Parse(layout, value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Date(parsed.Year(), parsed.Month(), parsed.Day(), 0, 0, 0, 0, time.UTC), nil
+}
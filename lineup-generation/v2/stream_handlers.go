@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+	"net/http"
+	"encoding/json"
+
+	p "v2/population"
+	u "v2/utils"
+)
+
+// Function to register the route that streams per-generation progress as Server-Sent Events before sending the final lineup
+func RegisterStreamRoutes() {
+
+	http.HandleFunc("/generate-lineup/stream", func(w http.ResponseWriter, r *http.Request) {
+
+		if r.Method == "OPTIONS" {
+			w.Header().Set("Access-Control-Allow-Origin", "*")
+			w.Header().Set("Access-Control-Allow-Methods", "POST")
+			w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+			return
+		}
+
+		// Set CORS headers for actual request
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			http.Error(w, "Streaming is not supported", http.StatusInternalServerError)
+			return
+		}
+
+		var request u.ReqBody
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			fmt.Println(err)
+			WriteBadRequest(w, "Failed to decode request body")
+			return
+		}
+
+		// Reject bad optimizer settings before committing to a stream
+		if _, err := request.Optimizer.Resolve(); err != nil {
+			WriteError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Connection", "keep-alive")
+		w.WriteHeader(http.StatusOK)
+		flusher.Flush()
+
+		// Islands report concurrently, so events have to be written one at a time
+		var mu sync.Mutex
+		send := func(event string, value any) {
+			mu.Lock()
+			defer mu.Unlock()
+			WriteEvent(w, event, value)
+			flusher.Flush()
+		}
+
+		response, err := OptimizeStreaming(r.Context(), request, func(progress p.Progress) {
+			send("progress", progress)
+		})
+		if err != nil {
+			_, code := ErrorStatus(err)
+			send("error", ErrorResponse{Error: err.Error(), Code: code})
+			return
+		}
+		send("result", response)
+	})
+}
+
+// Function to write a single Server-Sent Event with a JSON-encoded payload
+func WriteEvent(w http.ResponseWriter, event string, value any) {
+
+	json_data, err := json.Marshal(value)
+	if err != nil {
+		fmt.Fprintf(w, "event: error\ndata: {\"error\": \"Failed to encode event\"}\n\n")
+		return
+	}
+	fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, json_data)
+}
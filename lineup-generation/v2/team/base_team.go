@@ -1,6 +1,8 @@
 package team
 
 import (
+	"context"
+	"fmt"
 	"sort"
 	d "v2/data"
 	l "v2/resources"
@@ -14,12 +16,103 @@ type BaseTeam struct {
 	StreamablePlayers []d.Player
 	Score 			  		int
 	Week 			  			string
+	StartDay          int
+	AcquisitionsUsed  int
+	AcquisitionLimit  d.AcquisitionLimit
+	Schedule          *d.SeasonSchedule
+	Template          *d.RosterTemplate
+	ReturnDays        map[string]int
+	IRPlayers         []d.Player
+	Activations       []Activation
+	ForcedDrops       map[int]int
+	PlayProbabilities d.PlayProbabilities
+	Availability      map[int]map[string]float64
+	Projector         d.Projector
+	Projections       map[int]map[string]float64
+	Slotting          string
 }
 
-func InitBaseTeam(league_id int, espn_s2 string, swid string, team_name string, year int, fa_count int, week string, threshold float64) *BaseTeam {
+// Struct for how a team is set up in its league. The zero value means the defaults: the standard ESPN layout, the default chance of playing
+// for each injury designation, season average projections, filling the most restrictive slots and the league's usual acquisition limit with none used
+type BaseTeamOptions struct {
+	Template          *d.RosterTemplate
+	PlayProbabilities d.PlayProbabilities
+	Projector         d.Projector
+	Slotting          string
+	AcquisitionLimit  d.AcquisitionLimit
+	AcquisitionsUsed  int
+}
+
+// Function to initialize a BaseTeam with players from any provider. Only the days from start_day to the end of the week are optimized
+func InitBaseTeam(ctx context.Context, provider d.PlayerProvider, schedule *d.SeasonSchedule, options BaseTeamOptions, league d.LeagueInfo, fa_count int, week string, start_day int, threshold float64) (*BaseTeam, error) {
+
+	roster_map, free_agents, err := FetchPlayers(ctx, provider, schedule, league, fa_count, week, start_day)
+	if err != nil {
+		return nil, err
+	}
+
+	return BuildBaseTeam(schedule, options, roster_map, free_agents, week, start_day, threshold), nil
+}
+
+// Function to fetch the roster and free agents for a team that is about to be optimized from start_day on, without slotting anyone yet
+func FetchPlayers(ctx context.Context, provider d.PlayerProvider, schedule *d.SeasonSchedule, league d.LeagueInfo, fa_count int, week string, start_day int) (map[string]d.Player, []d.Player, error) {
+
+	// Make sure there are days left to optimize before fetching anything
+	if err := checkStartDay(schedule, week, start_day); err != nil {
+		return nil, nil, err
+	}
+
+	roster_map, free_agents, err := provider.FetchPlayers(ctx, league, fa_count)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := checkRoster(roster_map, league); err != nil {
+		return nil, nil, err
+	}
+
+	return roster_map, free_agents, nil
+}
+
+// Function to make sure start_day is one of the days of the week
+func checkStartDay(schedule *d.SeasonSchedule, week string, start_day int) error {
+	if game_span := schedule.GetGameSpan(week); start_day < 0 || start_day > game_span {
+		return fmt.Errorf("%w: day %d of a week with days 0-%d", d.ErrInvalidStartDay, start_day, game_span)
+	}
+	return nil
+}
+
+// Function to never optimize an empty roster. Whichever provider the players came from, a league with no roster under the team name means the team name is wrong
+func checkRoster(roster_map map[string]d.Player, league d.LeagueInfo) error {
+	if len(roster_map) == 0 {
+		return fmt.Errorf("%w: %q", d.ErrTeamNotFound, league.TeamName)
+	}
+	return nil
+}
+
+// Function to build a BaseTeam from a roster and free agents that have already been fetched
+func BuildBaseTeam(schedule *d.SeasonSchedule, options BaseTeamOptions, roster_map map[string]d.Player, free_agents []d.Player, week string, start_day int, threshold float64) *BaseTeam {
+
+	bt := &BaseTeam{
+		Schedule:          schedule,
+		Template:          options.Template,
+		PlayProbabilities: options.PlayProbabilities,
+		Projector:         options.Projector,
+		Slotting:          options.Slotting,
+		AcquisitionLimit:  options.AcquisitionLimit,
+		AcquisitionsUsed:  options.AcquisitionsUsed,
+		StartDay:          start_day,
+	}
+
+	// Rewrite every player's positions as the slots they can fill in this league
+	bt.RosterMap = make(map[string]d.Player, len(roster_map))
+	for name, player := range roster_map {
+		bt.RosterMap[name] = bt.GetRosterTemplate().FitPlayer(player)
+	}
+	bt.FreeAgents = make([]d.Player, len(free_agents))
+	for i, player := range free_agents {
+		bt.FreeAgents[i] = bt.GetRosterTemplate().FitPlayer(player)
+	}
 
-	bt := &BaseTeam{}
-	bt.RosterMap, bt.FreeAgents = d.FetchData(league_id, espn_s2, swid, team_name, year, fa_count)
 	bt.OptimizeSlotting(week, threshold)
 	bt.FindUnusedPositions()
 	bt.CalculateOptimalScore()
@@ -28,29 +121,97 @@ func InitBaseTeam(league_id int, espn_s2 string, swid string, team_name string,
 	return bt
 }
 
+// Function to initialize a BaseTeam from the mock data in the resources package against the schedule in ScheduleMap
 func InitBaseTeamMock(week string, threshold float64) *BaseTeam {
 
-	bt := &BaseTeam{}
-	bt.RosterMap = l.LoadRosterMap("/Users/jameskendrick/Code/cv/features/lineup-generation/v2/resources/mock_roster.json")
-	bt.FreeAgents = l.LoadFreeAgents("/Users/jameskendrick/Code/cv/features/lineup-generation/v2/resources/mock_freeagents.json")
-	bt.OptimizeSlotting(week, threshold)
-	bt.FindUnusedPositions()
-	bt.CalculateOptimalScore()
-	bt.Week = week
+	provider := d.FileProvider{RosterPath: l.MockRosterPath(), FreeAgentsPath: l.MockFreeAgentsPath()}
+	bt, err := InitBaseTeam(context.Background(), provider, &d.ScheduleMap, BaseTeamOptions{}, d.LeagueInfo{}, 0, week, 0, threshold)
+	if err != nil {
+		fmt.Println("Error loading mock team:", err)
+		return BuildBaseTeam(&d.ScheduleMap, BaseTeamOptions{}, map[string]d.Player{}, []d.Player{}, week, 0, threshold)
+	}
 
 	return bt
 }
 
+// Function to get the schedule the team is being optimized against, falling back to ScheduleMap for teams built by hand
+func (t *BaseTeam) GetSchedule() *d.SeasonSchedule {
+	if t.Schedule == nil {
+		return &d.ScheduleMap
+	}
+	return t.Schedule
+}
+
+// Layout used by teams that weren't given one
+var default_template = d.DefaultRosterTemplate()
+
+// Function to get the league's roster layout, falling back to the default for teams that weren't given one
+func (t *BaseTeam) GetRosterTemplate() *d.RosterTemplate {
+	if t.Template == nil {
+		return default_template
+	}
+	return t.Template
+}
+
+// Function to get the chance of playing for each injury designation, falling back to the defaults for teams that weren't given any
+func (t *BaseTeam) GetPlayProbabilities() d.PlayProbabilities {
+	if t.PlayProbabilities == nil {
+		return default_probabilities
+	}
+	return t.PlayProbabilities
+}
+
+// Function to get how players are projected, falling back to the season average for teams that weren't given a projector
+func (t *BaseTeam) GetProjector() d.Projector {
+	if t.Projector == nil {
+		return d.SeasonAverage{}
+	}
+	return t.Projector
+}
+
+// Function to get how the team chooses who starts, falling back to filling the most restrictive slots for teams that weren't given a mode
+func (t *BaseTeam) GetSlotting() string {
+	if t.Slotting == "" {
+		return d.SlottingRestrictive
+	}
+	return t.Slotting
+}
+
+// Chances used by teams that weren't given any
+var default_probabilities = d.DefaultPlayProbabilities()
+
+// Function to get the league's acquisition limit, falling back to the default for teams that weren't given one
+func (t *BaseTeam) GetAcquisitionLimit() d.AcquisitionLimit {
+	if t.AcquisitionLimit.Period == "" {
+		return d.DefaultAcquisitionLimit(t.GetSchedule().GetGameSpan(t.Week))
+	}
+	return t.AcquisitionLimit
+}
+
 
 // Finds available slots and players to experiment with on a roster when considering undroppable players and restrictive positions
 func (t *BaseTeam) OptimizeSlotting(week string, threshold float64) {
 
+	// Work out who is coming back from injury this week and who needs to be activated from IR
+	t.FindReturnDays(week)
+	t.PlanActivations(threshold)
+	game_span := t.GetSchedule().GetGameSpan(week)
+	t.FindAvailability(game_span)
+	t.FindProjections(week, game_span)
+
 	// Convert RosterMap to slices and abstract out IR spot. For the first day, pass all players to get_available_slots
 	var streamable_players []d.Player
 	var sorted_good_players []d.Player
 	for _, player := range t.RosterMap {
 
-		if player.Injured {
+		// Players on IR only play once they are activated, and injured players only once they are back
+		if player.OnIR {
+			if t.IsActivated(player, game_span) {
+				sorted_good_players = append(sorted_good_players, player)
+			}
+			continue
+		}
+		if !t.IsAvailable(player, game_span) {
 			continue
 		}
 
@@ -61,20 +222,26 @@ func (t *BaseTeam) OptimizeSlotting(week string, threshold float64) {
 		}
 	}
 
-	// Sort good players by average points
+	// Sort good players by average points, breaking ties by name since map iteration order is random
 	sort.Slice(sorted_good_players, func(i, j int) bool {
+		if sorted_good_players[i].AvgPoints == sorted_good_players[j].AvgPoints {
+			return sorted_good_players[i].Name < sorted_good_players[j].Name
+		}
 		return sorted_good_players[i].AvgPoints > sorted_good_players[j].AvgPoints
 	})
 
 	return_table := make(map[int]map[string]d.Player)
 
-	// Fill return table and put extra IR players on bench
-	for i := 0; i <= d.ScheduleMap.GetGameSpan(week); i++ {
+	// Fill return table and put extra IR players on bench, skipping the days that have already been played
+	for i := t.StartDay; i <= game_span; i++ {
 		return_table[i] = t.GetAvailableSlots(sorted_good_players, i, week)
 	}
 
-	// Sort the streamable players by average points
+	// Sort the streamable players by average points, again breaking ties by name
 	sort.Slice(streamable_players, func(i, j int) bool {
+		if streamable_players[i].AvgPoints == streamable_players[j].AvgPoints {
+			return streamable_players[i].Name < streamable_players[j].Name
+		}
 		return streamable_players[i].AvgPoints > streamable_players[j].AvgPoints
 	})
 	t.StreamablePlayers = streamable_players
@@ -93,60 +260,33 @@ type FitPlayersContext struct {
 func (t *BaseTeam) GetAvailableSlots(players []d.Player, day int, week string) map[string]d.Player {
 
 	// Priority order of most restrictive positions to funnel streamers into flexible positions
-	position_order := []string{"PG", "SG", "SF", "PF", "G", "F", "C", "UT1", "UT2", "UT3", "BE1", "BE2", "BE3"} // For players playing
+	position_order := t.GetRosterTemplate().SlotOrder()
 	
 	var playing []d.Player
 
 	for _, player := range players {
 
-		// Checks if the player is playing on the given day
-		if d.ScheduleMap.IsPlaying(week, day, player.Team){
+		// Checks if the player is playing on the given day and is healthy or back from IR by then
+		if t.GetSchedule().IsPlaying(week, day, player.Team) && t.IsAvailable(player, day) && (!player.OnIR || t.IsActivated(player, day)) {
 			playing = append(playing, player)
 		}
 	}
 
-	// Find most restrictive positions for players playing
-	optimal_slotting := func (playing []d.Player) map[string]d.Player {
-
-		sort.Slice(playing, func(i, j int) bool {
-			return len(playing[i].ValidPositions) < len(playing[j].ValidPositions)
-		})
-
-		// Create struct to keep track of state across recursive function calls
-		max_score := t.CalculateMaxScore(playing)
-		p_context := &FitPlayersContext{
-			BestLineup: make(map[string]d.Player), 
-			TopScore: 0, 
-			MaxScore: max_score, 
-			EarlyExit: false,
-		}
-	
-		// Recursive function call
-		t.FitPlayers(playing, make(map[string]d.Player), position_order, p_context, 0)
-	
-		// Create response map and fill with best lineup or empty strings for unused positions except for bench spots
-		response := make(map[string]d.Player)
-		filter := map[string]bool{"BE1": true, "BE2": true, "BE3": true}
-		for _, pos := range position_order {
-
-			if value, ok := p_context.BestLineup[pos]; ok {
-				response[pos] = value
-				continue
-			}
-			if _, ok := filter[pos]; !ok {
-				response[pos] = d.Player{}
-			}
-		}
+	// Fill the most restrictive positions with the players playing
+	lineup := t.MatchPlayers(playing, position_order, day)
 
-		return response
-	
-	}(playing)
+	// Create response map and fill with best lineup or empty players for unused positions
+	optimal_slotting := make(map[string]d.Player)
+	for _, pos := range position_order {
+		optimal_slotting[pos] = lineup[pos]
+	}
 
 	return optimal_slotting
 
 }
 
-// Recursive backtracking function to find most restrictive positions for players
+// Recursive backtracking function to find most restrictive positions for players. GetAvailableSlots uses MatchPlayers instead, which
+// finds a lineup just as restrictive without trying every assignment
 func (t *BaseTeam) FitPlayers(players []d.Player, cur_lineup map[string]d.Player, position_order []string, ctx *FitPlayersContext, index int) {
 
 	// If we have found a lineup that has the max score, we can send returns to all other recursive calls
@@ -154,8 +294,8 @@ func (t *BaseTeam) FitPlayers(players []d.Player, cur_lineup map[string]d.Player
 		return
 	}
 	
-	// If all players have been given positions, check if the current lineup is better than the best lineup
-	if len(players) == 0 {
+	// If all players have been given positions, or there are no positions left for the rest, check if the current lineup is better than the best lineup
+	if len(players) == 0 || index == len(position_order) {
 		score := t.ScoreRoster(cur_lineup)
 		// fmt.Println("Score:", score, "Max score:", ctx.MaxScore)
 		if score > ctx.TopScore {
@@ -203,20 +343,11 @@ func (t *BaseTeam) FitPlayers(players []d.Player, cur_lineup map[string]d.Player
 // Function to score a roster based on restricitveness of positions
 func (t *BaseTeam) ScoreRoster(roster map[string]d.Player) int {
 
-	// Scoring system
-	score_map := make(map[string]int)
-
-	scoring_groups := [][]string{{"PG", "SG", "SF", "PF"}, {"G", "F"}, {"C"}, {"UT1", "UT2", "UT3"}, {"BE1", "BE2", "BE3"}}
-	for score, group := range scoring_groups {
-		for _, position := range group {
-			score_map[position] = 5 - score
-		}
-	}
-
-	// Score roster
+	// Score roster by the priority of each slot that is filled
+	template := t.GetRosterTemplate()
 	score := 0
 	for pos := range roster {
-		score += score_map[pos]
+		score += template.Priority(pos)
 	}
 
 	return score
@@ -224,29 +355,14 @@ func (t *BaseTeam) ScoreRoster(roster map[string]d.Player) int {
 
 // Function to calculate the max restrictiveness score for a given set of players
 func (t *BaseTeam) CalculateMaxScore(players []d.Player) int {
-
-	size := len(players)
-
-	// Max score calulation corresponding with scoring_groups in score_roster
-	switch {
-		case size <= 4:
-			return size * 5
-		case size <= 6:
-			return 20 + ((size - 4) * 4)
-		case size <= 7:
-			return 28 + ((size - 6) * 3)
-		case size <= 10:
-			return 31 + ((size - 7) * 2)
-		default:
-			return 37 + (size - 10)
-	}
+	return t.GetRosterTemplate().MaxScore(len(players))
 }
 
 // Function to get the unused positions from the optimal slotting for good players playing for the week
 func (t *BaseTeam) FindUnusedPositions() {
 
 	// Order that the slice should be in
-	order := []string{"PG", "SG", "SF", "PF", "C", "G", "F", "UT1", "UT2", "UT3"}
+	order := t.GetRosterTemplate().StarterSlots()
 
 	// Create map to keep track of unused positions
 	unused_positions := make(map[int]map[string]bool)
@@ -271,12 +387,12 @@ func (t *BaseTeam) FindUnusedPositions() {
 	t.UnusedPositions = unused_positions
 }
 
-// Function to calculate the score of the optimal players for the week
+// Function to calculate the expected score of the optimal players for the week
 func (t *BaseTeam) CalculateOptimalScore() {
 	total_score := 0.0
-	for _, lineup := range t.OptimalSlotting {
+	for day, lineup := range t.OptimalSlotting {
 		for _, player := range lineup {
-			total_score += player.AvgPoints
+			total_score += t.ExpectedPoints(player, day)
 		}
 	}
 	t.Score = int(total_score)
@@ -0,0 +1,172 @@
+package team
+
+import (
+	"fmt"
+	"sort"
+	"time"
+	d "v2/data"
+)
+
+// Struct for a player coming back from IR during the week, who needs a roster spot from Day onwards
+type Activation struct {
+	Player d.Player
+	Day    int
+}
+
+// Function to work out the first day of the week that each injured player with a return date can play
+func (t *BaseTeam) FindReturnDays(week string) {
+
+	t.ReturnDays = make(map[string]int)
+	dates, err := t.GetSchedule().WeekDates(week)
+	if err != nil || len(dates) == 0 {
+		return
+	}
+
+	find_return_day := func(player d.Player) {
+		if !player.Injured || player.ReturnDate == "" {
+			return
+		}
+		return_date, err := time.Parse(d.RequestDateLayout, player.ReturnDate)
+		if err != nil {
+			fmt.Println("Error parsing return date for", player.Name, ":", err)
+			return
+		}
+
+		// Players coming back after the week are left out, just like players without a return date
+		if day := max(int(return_date.Sub(dates[0]).Hours()/24), 0); day < len(dates) {
+			t.ReturnDays[player.Name] = day
+		}
+	}
+
+	for _, player := range t.RosterMap {
+		find_return_day(player)
+	}
+	for _, player := range t.FreeAgents {
+		find_return_day(player)
+	}
+}
+
+// Function to get the chance that a player plays on a day. Players coming back from a longer injury are expected to play once they're back,
+// everyone else plays with the chance for their injury designation
+func (t *BaseTeam) PlayProbability(player d.Player, day int) float64 {
+	if return_day, ok := t.ReturnDays[player.Name]; ok {
+		if day < return_day {
+			return 0
+		}
+		return 1
+	}
+	return t.GetPlayProbabilities().Of(player)
+}
+
+// Function to check if a player has any chance of playing on a day
+func (t *BaseTeam) IsAvailable(player d.Player, day int) bool {
+	return t.PlayProbability(player, day) > 0
+}
+
+// Function to get the points a player is expected to score on a day if he has a game, weighted by the chance that he plays
+func (t *BaseTeam) ExpectedPoints(player d.Player, day int) float64 {
+	if probability, ok := t.Availability[day][player.Name]; ok {
+		return probability * t.ProjectedPoints(player, day)
+	}
+	return t.ProjectedPoints(player, day)
+}
+
+// Function to build the table of players who might not play on each remaining day, which the genes share for scoring
+func (t *BaseTeam) FindAvailability(game_span int) {
+
+	t.Availability = make(map[int]map[string]float64)
+	for day := t.StartDay; day <= game_span; day++ {
+		t.Availability[day] = make(map[string]float64)
+		add := func(player d.Player) {
+			if probability := t.PlayProbability(player, day); probability < 1 {
+				t.Availability[day][player.Name] = probability
+			}
+		}
+		for _, player := range t.RosterMap {
+			add(player)
+		}
+		for _, player := range t.FreeAgents {
+			add(player)
+		}
+	}
+}
+
+// Function to check if a player is both healthy and has a game on a day
+func (t *BaseTeam) CanPlay(player d.Player, day int) bool {
+	return t.GetSchedule().IsPlaying(t.Week, day, player.Team) && t.IsAvailable(player, day)
+}
+
+// Function to plan when the players on IR are activated. Only players who are good enough to start are brought back, and each one
+// takes an open roster spot if there is one or forces a streamer to be dropped on the day they come back if there isn't
+func (t *BaseTeam) PlanActivations(threshold float64) {
+
+	t.IRPlayers, t.Activations = nil, nil
+	t.ForcedDrops = make(map[int]int)
+
+	active := 0
+	for _, player := range t.RosterMap {
+		if !player.OnIR {
+			active++
+			continue
+		}
+		t.IRPlayers = append(t.IRPlayers, player)
+
+		return_day := 0
+		if player.Injured {
+			day, ok := t.ReturnDays[player.Name]
+			if !ok {
+				continue
+			}
+			return_day = day
+		}
+		if player.AvgPoints > threshold {
+			t.Activations = append(t.Activations, Activation{Player: player, Day: max(return_day, t.StartDay)})
+		}
+	}
+
+	sort.Slice(t.IRPlayers, func(i, j int) bool {
+		return t.IRPlayers[i].Name < t.IRPlayers[j].Name
+	})
+	sort.Slice(t.Activations, func(i, j int) bool {
+		if t.Activations[i].Day == t.Activations[j].Day {
+			return t.Activations[i].Player.Name < t.Activations[j].Player.Name
+		}
+		return t.Activations[i].Day < t.Activations[j].Day
+	})
+
+	// The first activations fill any open roster spots, the rest need a drop
+	template := t.GetRosterTemplate()
+	open_spots := max(len(template.StarterSlots())+len(template.SlotsOfKind(d.SlotBench))-active, 0)
+	for i, activation := range t.Activations {
+		if i >= open_spots {
+			t.ForcedDrops[activation.Day]++
+		}
+	}
+}
+
+// Function to check if a player on IR has been activated by a day
+func (t *BaseTeam) IsActivated(player d.Player, day int) bool {
+	for _, activation := range t.Activations {
+		if activation.Player.Name == player.Name {
+			return day >= activation.Day
+		}
+	}
+	return false
+}
+
+// Function to get who is in each IR slot on a day
+func (t *BaseTeam) IRSlotting(day int) map[string]d.Player {
+
+	slots := t.GetRosterTemplate().SlotsOfKind(d.SlotIR)
+	slotting := make(map[string]d.Player)
+	for _, player := range t.IRPlayers {
+		if t.IsActivated(player, day) {
+			continue
+		}
+		if len(slotting) == len(slots) {
+			break
+		}
+		slotting[slots[len(slotting)]] = player
+	}
+	return slotting
+}
@@ -0,0 +1,51 @@
+package team
+
+import (
+	"context"
+	d "v2/data"
+)
+
+// Threshold that makes every player on the opponent's roster a core player, since we don't stream for them
+const OpponentThreshold = -1.0
+
+// Function to initialize a BaseTeam for the opponent in a matchup, slotted the same way as our own team so that their total can be projected.
+// We don't stream for them, so only their roster is fetched
+func InitOpponent(ctx context.Context, provider d.PlayerProvider, schedule *d.SeasonSchedule, options BaseTeamOptions, league d.LeagueInfo, week string, start_day int) (*BaseTeam, error) {
+
+	if err := checkStartDay(schedule, week, start_day); err != nil {
+		return nil, err
+	}
+
+	roster_map, err := provider.FetchRoster(ctx, league)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkRoster(roster_map, league); err != nil {
+		return nil, err
+	}
+
+	return BuildBaseTeam(schedule, options, roster_map, nil, week, start_day, OpponentThreshold), nil
+}
+
+// Function to get the variance of the points a player scores in a game. It comes from both how well he plays, with a spread of cv
+// times his projection, and whether he plays at all
+func GameVariance(chance float64, points float64, cv float64) float64 {
+	spread := cv * points
+	return chance*spread*spread + chance*(1-chance)*points*points
+}
+
+// Function to project the total points of the core players over the rest of the week, along with its variance
+func (t *BaseTeam) ProjectTotal(cv float64) (float64, float64) {
+	mean, variance := 0.0, 0.0
+	for day, lineup := range t.OptimalSlotting {
+		for _, player := range lineup {
+			if player.Name == "" {
+				continue
+			}
+			chance := t.PlayProbability(player, day)
+			mean += chance * t.ProjectedPoints(player, day)
+			variance += GameVariance(chance, t.ProjectedPoints(player, day), cv)
+		}
+	}
+	return mean, variance
+}
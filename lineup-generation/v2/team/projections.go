@@ -0,0 +1,36 @@
+package team
+
+import (
+	"fmt"
+	d "v2/data"
+)
+
+// Function to build the table of projected points for every player on each remaining day, which the genes share for scoring
+func (t *BaseTeam) FindProjections(week string, game_span int) {
+
+	t.Projections = make(map[int]map[string]float64)
+	dates, err := t.GetSchedule().WeekDates(week)
+	if err != nil {
+		fmt.Println("Error finding dates to project:", err)
+		return
+	}
+
+	projector := t.GetProjector()
+	for day := t.StartDay; day <= game_span && day < len(dates); day++ {
+		t.Projections[day] = make(map[string]float64, len(t.RosterMap)+len(t.FreeAgents))
+		for _, player := range t.RosterMap {
+			t.Projections[day][player.Name] = projector.Project(player, dates[day])
+		}
+		for _, player := range t.FreeAgents {
+			t.Projections[day][player.Name] = projector.Project(player, dates[day])
+		}
+	}
+}
+
+// Function to get the points a player is projected to score on a day, falling back to his season average if he wasn't projected
+func (t *BaseTeam) ProjectedPoints(player d.Player, day int) float64 {
+	if points, ok := t.Projections[day][player.Name]; ok {
+		return points
+	}
+	return player.AvgPoints
+}
@@ -0,0 +1,59 @@
+package team
+
+import (
+	d "v2/data"
+	u "v2/utils"
+)
+
+// Function to slot players into the given slots on a day as a max-weight matching of players to slots. By default the lineup fills the
+// most restrictive slots it can, which is what FitPlayers searches for, and among lineups that do that equally well it starts the
+// players expected to score the most. When slotting for points the two are the other way around
+func (t *BaseTeam) MatchPlayers(players []d.Player, slots []string, day int) map[string]d.Player {
+
+	template := t.GetRosterTemplate()
+	points := make([]float64, len(players))
+	for i, player := range players {
+		points[i] = max(t.ExpectedPoints(player, day), 0)
+	}
+
+	// The tiebreaker is scaled so that all of it together is worth less than the smallest step in what is being maximized. Every
+	// player counts for at least one point of it so that starting him always beats leaving the slot empty
+	var weight func(i int, slot string) float64
+	if t.GetSlotting() == d.SlottingPoints {
+		total := 1.0
+		for _, slot := range slots {
+			total += float64(template.Priority(slot)) + 1
+		}
+		scale := 1e-6 / total
+		weight = func(i int, slot string) float64 {
+			return points[i] + scale*(float64(template.Priority(slot))+1)
+		}
+	} else {
+		total := 1.0
+		for i := range players {
+			total += points[i] + 1
+		}
+		scale := 1 / total
+		weight = func(i int, slot string) float64 {
+			return float64(template.Priority(slot)) + scale*(points[i]+1)
+		}
+	}
+
+	weights := make([][]float64, len(players))
+	for i, player := range players {
+		weights[i] = make([]float64, len(slots))
+		for j, slot := range slots {
+			if player.PlaysPosition(slot) {
+				weights[i][j] = weight(i, slot)
+			}
+		}
+	}
+
+	lineup := make(map[string]d.Player)
+	for i, j := range u.MaxWeightMatching(weights) {
+		if j >= 0 {
+			lineup[slots[j]] = players[i]
+		}
+	}
+	return lineup
+}
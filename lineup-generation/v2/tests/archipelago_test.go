@@ -0,0 +1,148 @@
+package tests
+
+import (
+	"context"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	d "v2/data"
+	p "v2/population"
+	l "v2/resources"
+	"v2/team"
+	u "v2/utils"
+)
+
+func TestArchipelagoRingMigration(t *testing.T) {
+	bt := loadMockTeam("5", 32.0)
+
+	config := u.DefaultOptimizerConfig()
+	config.NumIslands = 3
+	config.NumEmigrants = 1
+	a, _ := p.InitArchipelago(context.Background(), bt, config, rand.New(rand.NewSource(7)))
+
+	// Remember each island's best chromosome before migrating
+	best := make([]*p.Chromosome, len(a.Islands))
+	for i, island := range a.Islands {
+		island.SortByFitness()
+		best[i] = island.Population[island.NumChromosomes-1]
+	}
+
+	a.Migrate()
+
+	// Each island should now hold a copy of its predecessor's best chromosome
+	for i, island := range a.Islands {
+		predecessor := best[(i+len(a.Islands)-1)%len(a.Islands)]
+		found := false
+		for _, chromosome := range island.Population {
+			if chromosome == predecessor {
+				t.Errorf("Island %d shares a chromosome pointer with its neighbor", i)
+			}
+			if chromosome.FitnessScore == predecessor.FitnessScore && chromosome.TotalAcquisitions == predecessor.TotalAcquisitions {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("Island %d did not receive its predecessor's best chromosome", i)
+		}
+		if island.NumChromosomes != config.PopulationSize || len(island.Population) != config.PopulationSize {
+			t.Errorf("Island %d changed size during migration", i)
+		}
+	}
+}
+
+func TestArchipelagoEvolveAndMerge(t *testing.T) {
+	bt := loadMockTeam("5", 32.0)
+
+	config := u.DefaultOptimizerConfig()
+	config.NumIslands = 4
+	config.MigrationTopology = u.TopologyFull
+	config.MigrationInterval = 2
+	a, _ := p.InitArchipelago(context.Background(), bt, config, rand.New(rand.NewSource(7)))
+
+	if neighbors := a.Neighbors(0); len(neighbors) != 3 {
+		t.Errorf("Fully connected island should have 3 neighbors, got %d", len(neighbors))
+	}
+
+	a.Evolve(context.Background(), bt, 5)
+	ev := a.Merge()
+	if ev.NumChromosomes != config.NumIslands*config.PopulationSize || len(ev.Population) != ev.NumChromosomes {
+		t.Errorf("Merged population size is incorrect: %d", ev.NumChromosomes)
+	}
+
+	// Make sure the migrants are still valid chromosomes
+	for _, chromosome := range ev.Population {
+		for _, gene := range chromosome.Genes {
+			if gene.GetNumStreamers() != len(bt.StreamablePlayers) {
+				t.Errorf("Streamer count is incorrect")
+			}
+		}
+	}
+}
+
+func TestArchipelagoReportsProgress(t *testing.T) {
+	bt := loadMockTeam("5", 32.0)
+
+	config := u.DefaultOptimizerConfig()
+	config.NumIslands = 2
+	a, _ := p.InitArchipelago(context.Background(), bt, config, rand.New(rand.NewSource(7)))
+
+	// Collect the progress reports from both islands
+	var mu sync.Mutex
+	reports := make(map[int][]p.Progress)
+	a.SetProgressFunc(func(progress p.Progress) {
+		mu.Lock()
+		defer mu.Unlock()
+		reports[progress.Island] = append(reports[progress.Island], progress)
+	})
+	a.Evolve(context.Background(), bt, 3)
+
+	// The merged population should keep counting generations where the islands left off
+	ev := a.Merge()
+	ev.Evolve(context.Background(), bt)
+
+	for island := 0; island < config.NumIslands; island++ {
+		if len(reports[island]) != 3 {
+			t.Fatalf("Island %d reported %d generations", island, len(reports[island]))
+		}
+		for i, progress := range reports[island] {
+			if progress.Generation != i+1 {
+				t.Errorf("Island %d reported generation %d out of order", island, progress.Generation)
+			}
+			if progress.MedianFitness > progress.BestFitness {
+				t.Errorf("Median fitness is above the best fitness")
+			}
+		}
+	}
+	if merged := reports[p.MergedIsland]; len(merged) != 1 || merged[0].Generation != 4 {
+		t.Errorf("Merged population progress is incorrect: %+v", merged)
+	}
+}
+
+func TestArchipelagoNoFreeAgents(t *testing.T) {
+	d.InitSchedule("../static/schedule24-25.json")
+
+	// A free agents file with nobody in it leaves the roster as it is rather than crashing the run
+	free_agents_path := filepath.Join(t.TempDir(), "free_agents.json")
+	if err := os.WriteFile(free_agents_path, []byte("[]"), 0o644); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	provider := d.FileProvider{RosterPath: l.MockRosterPath(), FreeAgentsPath: free_agents_path}
+	bt, err := team.InitBaseTeam(context.Background(), provider, &d.ScheduleMap, team.BaseTeamOptions{}, d.LeagueInfo{}, 50, "5", 0, 32.0)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	config := u.DefaultOptimizerConfig()
+	archipelago, err := p.InitArchipelago(context.Background(), bt, config, rand.New(rand.NewSource(7)))
+	if err == nil {
+		err = archipelago.Evolve(context.Background(), bt, config.IslandGenerations)
+	}
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if best := archipelago.Merge().BestValid(); best != nil && best.TotalAcquisitions != 0 {
+		t.Errorf("Expected no moves without free agents, got %d", best.TotalAcquisitions)
+	}
+}
@@ -0,0 +1,110 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	d "v2/data"
+	l "v2/resources"
+	p "v2/population"
+	"v2/team"
+)
+
+func TestPlayProbabilities(t *testing.T) {
+
+	// Designations are matched however they're written
+	probabilities := d.PlayProbabilities{"day-to-day": 0.7}.Merge()
+	if probabilities[d.StatusDayToDay] != 0.7 || probabilities[d.StatusQuestionable] != 0.5 {
+		t.Errorf("Merged chances are incorrect: %v", probabilities)
+	}
+	if chance := probabilities.Of(d.Player{InjuryStatus: "DAY_TO_DAY"}); chance != 0.7 {
+		t.Errorf("Expected ESPN's DAY_TO_DAY to be DTD, got %v", chance)
+	}
+
+	// Players without a known designation fall back to whether they're injured
+	if probabilities.Of(d.Player{Injured: true}) != 0 || probabilities.Of(d.Player{}) != 1 {
+		t.Errorf("Players without a designation have the wrong chance of playing")
+	}
+
+	for _, bad := range []d.PlayProbabilities{{"SUSPENDED": 0.5}, {"QUESTIONABLE": 1.5}, {"OUT": -0.1}} {
+		if err := bad.Validate(); !errors.Is(err, d.ErrInvalidPlayProbabilities) {
+			t.Errorf("Expected ErrInvalidPlayProbabilities for %v, got %v", bad, err)
+		}
+	}
+}
+
+// Function to build the mock team for week 5 with its best player listed as questionable and its worst streamer as doubtful
+func loadQuestionableTeam(t *testing.T, probabilities d.PlayProbabilities) *team.BaseTeam {
+	d.InitSchedule("../static/schedule24-25.json")
+
+	roster_map := l.LoadRosterMap(l.MockRosterPath())
+	for name, status := range map[string]string{"Shai Gilgeous-Alexander": d.StatusQuestionable, "Vince Williams Jr.": d.StatusDoubtful} {
+		player := roster_map[name]
+		player.Injured, player.InjuryStatus = true, status
+		roster_map[name] = player
+	}
+
+	provider := d.MemoryProvider{Roster: roster_map, FreeAgents: l.LoadFreeAgents(l.MockFreeAgentsPath())}
+	bt, err := team.InitBaseTeam(context.Background(), provider, &d.ScheduleMap, team.BaseTeamOptions{PlayProbabilities: probabilities}, d.LeagueInfo{}, 50, "5", 0, 32.0)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return bt
+}
+
+func TestExpectedPoints(t *testing.T) {
+	healthy := loadMockTeam("5", 32.0)
+	bt := loadQuestionableTeam(t, nil)
+
+	// A questionable player still starts, but only counts for half of his points
+	found := false
+	expected_loss := 0.0
+	for day, lineup := range bt.OptimalSlotting {
+		for _, player := range lineup {
+			if player.Name == "Shai Gilgeous-Alexander" {
+				found = true
+				expected_loss += player.AvgPoints * 0.5
+				if points := bt.ExpectedPoints(player, day); math.Abs(points-player.AvgPoints*0.5) > 1e-9 {
+					t.Errorf("Expected points on day %d are %v", day, points)
+				}
+			}
+		}
+	}
+	if !found {
+		t.Fatalf("Questionable player was never slotted")
+	}
+	if diff := float64(healthy.Score-bt.Score) - expected_loss; math.Abs(diff) > 1 {
+		t.Errorf("Score should drop by %v, dropped by %d", expected_loss, healthy.Score-bt.Score)
+	}
+
+	// Streamers are scored on expected points too
+	chromosome := p.InitChromosome(bt)
+	for _, gene := range chromosome.Genes {
+		gene.InsertStreamablePlayers(bt)
+	}
+	chromosome.ScoreFitness()
+	total := 0.0
+	for _, gene := range chromosome.Genes {
+		for _, player := range gene.Roster {
+			chance := 1.0
+			if player.Name == "Vince Williams Jr." {
+				chance = 0.25
+			}
+			total += player.AvgPoints * chance
+		}
+	}
+	if chromosome.FitnessScore != float64(int(total)) {
+		t.Errorf("Fitness should be %v, got %v", int(total), chromosome.FitnessScore)
+	}
+
+	// A league that never plays questionable players should leave him out entirely
+	cautious := loadQuestionableTeam(t, d.PlayProbabilities{"QUESTIONABLE": 0}.Merge())
+	for day, lineup := range cautious.OptimalSlotting {
+		for _, player := range lineup {
+			if player.Name == "Shai Gilgeous-Alexander" {
+				t.Errorf("Questionable player slotted on day %d with no chance of playing", day)
+			}
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package tests
 
 import (
+	"context"
 	"fmt"
 	d "v2/data"
 	l "v2/resources"
@@ -20,7 +21,11 @@ func TestBTInitWAPI(t *testing.T) {
 	fa_count := 100
 	week := "1"
 	threshold := 30.0
-	bt := team.InitBaseTeam(league_id, espn_s2, swid, team_name, year, fa_count, week, threshold)
+	league := d.LeagueInfo{LeagueId: league_id, EspnS2: espn_s2, Swid: swid, TeamName: team_name, Year: year}
+	bt, err := team.InitBaseTeam(context.Background(), d.BackendProvider{}, &d.ScheduleMap, team.BaseTeamOptions{}, league, fa_count, week, 0, threshold)
+	if err != nil {
+		t.Fatalf("Failed to initialize BaseTeam: %v", err)
+	}
 
 	// Validate fields
 	BTFieldValidator(bt, t, "Anthony Edwards", "SG", 7, "MIN", threshold, "RosterMap")
@@ -85,7 +90,10 @@ func TestBTFetchData(t *testing.T) {
 	team_name := "James's Scary Team"
 	year := 2024
 	fa_count := 100
-	roster_map, free_agents := d.FetchData(league_id, espn_s2, swid, team_name, year, fa_count)
+	roster_map, free_agents, err := d.FetchData(context.Background(), league_id, espn_s2, swid, team_name, year, fa_count)
+	if err != nil {
+		t.Fatalf("Failed to fetch data: %v", err)
+	}
 	bt := &team.BaseTeam{
 		RosterMap: roster_map,
 		FreeAgents: free_agents,
@@ -0,0 +1,60 @@
+package tests
+
+import (
+	"testing"
+	"time"
+	"v2/cache"
+	d "v2/data"
+	u "v2/utils"
+)
+
+func TestCacheTTLAndEviction(t *testing.T) {
+	c := cache.New(2, 50*time.Millisecond)
+
+	c.Set("a", u.Response{Timestamp: "1/1/2025 1:00PM"})
+	c.Set("b", u.Response{})
+
+	// A hit should return the original response untouched
+	if response, ok := c.Get("a"); !ok || response.Timestamp != "1/1/2025 1:00PM" {
+		t.Errorf("Expected a hit with the original timestamp, got %v %+v", ok, response)
+	}
+
+	// "b" is now the least recently used entry so it should be evicted first
+	c.Set("c", u.Response{})
+	if _, ok := c.Get("b"); ok {
+		t.Errorf("Least recently used entry was not evicted")
+	}
+	if c.Len() != 2 {
+		t.Errorf("Cache grew past its size limit: %d", c.Len())
+	}
+
+	// Entries should expire once they outlive the TTL
+	time.Sleep(60 * time.Millisecond)
+	if _, ok := c.Get("a"); ok {
+		t.Errorf("Expired entry was returned")
+	}
+}
+
+func TestCacheKey(t *testing.T) {
+	req := u.ReqBody{LeagueId: 1, TeamName: "Team", Year: 2025, Week: "5", Threshold: 30.0}
+	config := u.DefaultOptimizerConfig()
+	free_agents := []d.Player{{Name: "FA", AvgPoints: 20.0, Team: "BOS"}}
+
+	// Keys should not depend on the order the roster map was built in
+	roster1 := map[string]d.Player{"A": {Name: "A", AvgPoints: 30.0}, "B": {Name: "B", AvgPoints: 25.0}}
+	roster2 := map[string]d.Player{"B": {Name: "B", AvgPoints: 25.0}, "A": {Name: "A", AvgPoints: 30.0}}
+	key := cache.Key(req, config, roster1, free_agents)
+	if key != cache.Key(req, config, roster2, free_agents) {
+		t.Errorf("Equivalent rosters produced different keys")
+	}
+
+	// But they should change when the players or the request change
+	roster2["B"] = d.Player{Name: "B", AvgPoints: 26.0}
+	if key == cache.Key(req, config, roster2, free_agents) {
+		t.Errorf("Different rosters produced the same key")
+	}
+	req.Threshold = 31.0
+	if key == cache.Key(req, config, roster1, free_agents) {
+		t.Errorf("Different thresholds produced the same key")
+	}
+}
@@ -0,0 +1,136 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	d "v2/data"
+	l "v2/resources"
+	p "v2/population"
+	"v2/team"
+)
+
+func TestCategoryLeagueValidate(t *testing.T) {
+
+	league := d.CategoryLeague{}.WithDefaults()
+	if len(league.Categories) != 9 || league.Scoring != d.ScoringScaled {
+		t.Errorf("Expected 9-cat scored on the scaled totals, got %+v", league)
+	}
+	if err := league.Validate(); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	for _, bad := range []d.CategoryLeague{
+		{Categories: []string{"PTS", "DD"}, Scoring: d.ScoringScaled},
+		{Categories: []string{"PTS", "PTS"}, Scoring: d.ScoringScaled},
+		{Categories: d.EightCat(), Scoring: d.ScoringWins},
+		{Categories: d.EightCat(), Scoring: "roto"},
+		{Categories: d.EightCat(), Scoring: "zscore"},
+	} {
+		if err := bad.Validate(); !errors.Is(err, d.ErrInvalidCategories) {
+			t.Errorf("Expected ErrInvalidCategories for %+v, got %v", bad, err)
+		}
+	}
+}
+
+func TestCategoryScoring(t *testing.T) {
+
+	// Percentages are worked out from the totals rather than averaged
+	var totals d.CategoryStats
+	totals.Add(d.CategoryStats{FGM: 10, FGA: 20}, 1)
+	totals.Add(d.CategoryStats{FGM: 1, FGA: 5}, 2)
+	if pct := totals.Value(d.CatFGPct); math.Abs(pct-12.0/30.0) > 1e-9 {
+		t.Errorf("Expected FG%% of 0.4, got %v", pct)
+	}
+
+	// Turnovers count against a player and fewer of them wins the category
+	pool := []d.Player{{Stats: d.CategoryStats{PTS: 10, TO: 1}}, {Stats: d.CategoryStats{PTS: 20, TO: 3}}}
+	scales := d.NewCategoryScales([]string{d.CatPoints, d.CatTurnovers}, pool)
+	if value := scales.Value(d.CategoryStats{PTS: 15, TO: 2}); math.Abs(value-1) > 1e-9 {
+		t.Errorf("An average game should be worth 3 standard deviations of points less 2 of turnovers, got %v", value)
+	}
+	if value := scales.Value(d.CategoryStats{PTS: 15, TO: 1}); value <= 1 {
+		t.Errorf("Fewer turnovers should be worth something, got %v", value)
+	}
+	if won := d.CategoriesWon([]string{d.CatPoints, d.CatTurnovers, d.CatBlocks}, d.CategoryStats{PTS: 100, TO: 10}, d.CategoryStats{PTS: 90, TO: 12}); won != 2.5 {
+		t.Errorf("Expected 2.5 categories won, got %v", won)
+	}
+}
+
+// Function to build the mock team for week 5 with stats made up from each player's average points
+func loadCategoryTeam(t *testing.T) *team.BaseTeam {
+	d.InitSchedule("../static/schedule24-25.json")
+
+	with_stats := func(player d.Player) d.Player {
+		player.Stats = d.CategoryStats{PTS: player.AvgPoints * 0.5, REB: player.AvgPoints * 0.2, AST: player.AvgPoints * 0.15, STL: 1, BLK: 0.5, TPM: 1.5, FGM: 7, FGA: 15, FTM: 3, FTA: 4, TO: player.AvgPoints * 0.05}
+		return player
+	}
+	roster_map := l.LoadRosterMap(l.MockRosterPath())
+	for name, player := range roster_map {
+		roster_map[name] = with_stats(player)
+	}
+	free_agents := l.LoadFreeAgents(l.MockFreeAgentsPath())
+	for i := range free_agents {
+		free_agents[i] = with_stats(free_agents[i])
+	}
+
+	provider := d.MemoryProvider{Roster: roster_map, FreeAgents: free_agents}
+	bt, err := team.InitBaseTeam(context.Background(), provider, &d.ScheduleMap, team.BaseTeamOptions{}, d.LeagueInfo{}, 50, "5", 0, 32.0)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return bt
+}
+
+func TestCategoryFitness(t *testing.T) {
+	bt := loadCategoryTeam(t)
+
+	chromosome := p.InitChromosome(bt)
+	for _, gene := range chromosome.Genes {
+		gene.InsertStreamablePlayers(bt)
+	}
+
+	// The value is of the week's totals, core players included
+	fitness := p.NewCategoryFitness(bt, d.CategoryLeague{})
+	totals := fitness.Core
+	for _, gene := range chromosome.Genes {
+		for _, player := range gene.Roster {
+			totals.Add(player.Stats, 1)
+		}
+	}
+	if score, value := fitness.Score(chromosome), fitness.Scales.Value(totals); math.Abs(score-value) > 1e-9 {
+		t.Errorf("Expected a value of %v, got %v", value, score)
+	}
+
+	// Playing another game from an average player, or even one a little below average, adds to the totals rather than counting against them
+	var average d.CategoryStats
+	pool := append([]d.Player(nil), bt.FreeAgents...)
+	for _, player := range bt.RosterMap {
+		pool = append(pool, player)
+	}
+	for _, player := range pool {
+		average.Add(player.Stats, 1/float64(len(pool)))
+	}
+	before := fitness.Score(chromosome)
+	gene := chromosome.Genes[0]
+	for _, share := range []float64{1, 0.9} {
+		var extra d.CategoryStats
+		extra.Add(average, share)
+		gene.Roster["Extra"] = d.Player{Name: "Extra", Stats: extra}
+		if after := fitness.Score(chromosome); after < before {
+			t.Errorf("Adding a game from a player at %v of average lowered the fitness from %v to %v", share, before, after)
+		}
+	}
+	delete(gene.Roster, "Extra")
+
+	// Against an opponent with nothing, every category but turnovers is won
+	empty := p.NewCategoryFitness(bt, d.CategoryLeague{Scoring: d.ScoringWins, Opponent: &d.CategoryStats{}})
+	if score := empty.Score(chromosome); math.Round(score) != 8 {
+		t.Errorf("Expected 8 categories won, got %v", score)
+	}
+	stacked := p.NewCategoryFitness(bt, d.CategoryLeague{Scoring: d.ScoringWins, Opponent: &d.CategoryStats{PTS: 1e4, REB: 1e4, AST: 1e4, STL: 1e4, BLK: 1e4, TPM: 1e4, FGM: 1e4, FGA: 1e4, FTM: 1e4, FTA: 1e4, TO: 1e4}})
+	if score := stacked.Score(chromosome); math.Round(score) != 1 {
+		t.Errorf("Expected only turnovers to be won, got %v", score)
+	}
+}
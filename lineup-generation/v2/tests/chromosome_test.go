@@ -215,4 +215,22 @@ func TestChromosomeSlim(t *testing.T) {
 	slim_chromosome := c.Slim()
 	fmt.Println(slim_chromosome[0])
 }
-	
\ No newline at end of file
+	
+func TestFindRandomPlayerToDrop(t *testing.T) {
+	bt := loadMockTeam("5", 32.0)
+
+	// A pickup on the first day of the week is the only one there is to drop
+	c := p.InitChromosome(bt)
+	pickup := d.Player{Name: "Pickup"}
+	c.Genes[0].NewPlayers = append(c.Genes[0].NewPlayers, pickup)
+	c.Genes[0].Acquisitions++
+	player, _, start, _ := c.FindRandomPlayerToDrop(rand.New(rand.NewSource(1)))
+	if player.Name != pickup.Name || start != 0 {
+		t.Errorf("Expected the first day's pickup to be found, got %q on gene %d", player.Name, start)
+	}
+
+	// With no pickups at all there is nobody to drop
+	if _, _, start, _ := p.InitChromosome(bt).FindRandomPlayerToDrop(rand.New(rand.NewSource(1))); start != -1 {
+		t.Errorf("Expected no player to drop, got gene %d", start)
+	}
+}
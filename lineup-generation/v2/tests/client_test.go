@@ -0,0 +1,197 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+	d "v2/data"
+)
+
+// Function to create a client for a test server with short timings
+func testClient(base_url string) *d.BackendClient {
+	return d.NewBackendClient(d.ClientConfig{
+		BaseURL:          base_url,
+		Timeout:          200 * time.Millisecond,
+		MaxRetries:       2,
+		BaseBackoff:      time.Millisecond,
+		MaxBackoff:       5 * time.Millisecond,
+		BreakerThreshold: 3,
+		BreakerCooldown:  100 * time.Millisecond,
+	})
+}
+
+func TestClientRetriesTransientFailures(t *testing.T) {
+
+	// The first call to each endpoint fails, the retry succeeds
+	var calls atomic.Int32
+	failed := map[string]*atomic.Bool{d.RosterPath: {}, d.FreeAgentsPath: {}}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if !failed[r.URL.Path].Swap(true) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		json.NewEncoder(w).Encode([]d.Player{{Name: "Player " + r.URL.Path}})
+	}))
+	defer server.Close()
+
+	roster_map, free_agents, err := testClient(server.URL).FetchData(context.Background(), d.LeagueInfo{TeamName: "Team"}, 10)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(roster_map) != 1 || len(free_agents) != 1 {
+		t.Errorf("Expected one rostered player and one free agent, got %d and %d", len(roster_map), len(free_agents))
+	}
+	if calls.Load() != 4 {
+		t.Errorf("Expected 4 calls, got %d", calls.Load())
+	}
+}
+
+func TestClientDoesNotRetryClientErrors(t *testing.T) {
+
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	client := testClient(server.URL)
+	_, err := client.PostPlayers(context.Background(), d.RosterPath, d.ReqMeta{})
+	if !errors.Is(err, d.ErrBadCredentials) {
+		t.Errorf("Expected ErrBadCredentials, got %v", err)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("Expected a single call, got %d", calls.Load())
+	}
+}
+
+func TestClientTimesOutEachAttempt(t *testing.T) {
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	defer server.Close()
+
+	start := time.Now()
+	_, err := testClient(server.URL).PostPlayers(context.Background(), d.RosterPath, d.ReqMeta{})
+	if !errors.Is(err, d.ErrBackendUnavailable) {
+		t.Errorf("Expected ErrBackendUnavailable, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 900*time.Millisecond {
+		t.Errorf("Attempts were not bounded by the timeout: %v", elapsed)
+	}
+}
+
+func TestClientBadURL(t *testing.T) {
+
+	// A request that can't even be built fails without being sent
+	if _, err := testClient("http://bad host").PostPlayers(context.Background(), d.RosterPath, d.ReqMeta{}); err == nil || errors.Is(err, d.ErrBackendUnavailable) {
+		t.Errorf("Expected an error creating the request, got %v", err)
+	}
+}
+
+func TestCircuitBreaker(t *testing.T) {
+
+	var healthy atomic.Bool
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		json.NewEncoder(w).Encode([]d.Player{})
+	}))
+	defer server.Close()
+	client := testClient(server.URL)
+
+	// Three failed attempts open the circuit, after which calls fail without reaching the server
+	client.PostPlayers(context.Background(), d.RosterPath, d.ReqMeta{})
+	if calls.Load() != 3 {
+		t.Fatalf("Expected 3 calls, got %d", calls.Load())
+	}
+	if _, err := client.PostPlayers(context.Background(), d.RosterPath, d.ReqMeta{}); !errors.Is(err, d.ErrCircuitOpen) || !errors.Is(err, d.ErrBackendUnavailable) {
+		t.Errorf("Expected ErrCircuitOpen, got %v", err)
+	}
+	if calls.Load() != 3 {
+		t.Errorf("Open circuit let a call through")
+	}
+
+	// After the cooldown a trial call goes through and closes the circuit again
+	healthy.Store(true)
+	time.Sleep(150 * time.Millisecond)
+	if _, err := client.PostPlayers(context.Background(), d.RosterPath, d.ReqMeta{}); err != nil {
+		t.Errorf("Expected trial call to succeed, got %v", err)
+	}
+	if _, err := client.PostPlayers(context.Background(), d.RosterPath, d.ReqMeta{}); err != nil {
+		t.Errorf("Expected closed circuit, got %v", err)
+	}
+}
+
+func TestCircuitBreakerBadBodyDuringTrial(t *testing.T) {
+
+	var state atomic.Int32 // 0 failing, 1 garbled, 2 healthy
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch state.Load() {
+		case 0:
+			w.WriteHeader(http.StatusBadGateway)
+		case 1:
+			w.Write([]byte("not json"))
+		default:
+			json.NewEncoder(w).Encode([]d.Player{})
+		}
+	}))
+	defer server.Close()
+	client := testClient(server.URL)
+
+	// Open the circuit, then let the trial call get a body that can't be decoded
+	client.PostPlayers(context.Background(), d.RosterPath, d.ReqMeta{})
+	state.Store(1)
+	time.Sleep(150 * time.Millisecond)
+	if _, err := client.PostPlayers(context.Background(), d.RosterPath, d.ReqMeta{}); err == nil || errors.Is(err, d.ErrCircuitOpen) {
+		t.Fatalf("Expected the trial call to fail decoding, got %v", err)
+	}
+	if _, err := client.PostPlayers(context.Background(), d.RosterPath, d.ReqMeta{}); !errors.Is(err, d.ErrCircuitOpen) {
+		t.Errorf("Expected a bad body to keep the circuit open, got %v", err)
+	}
+
+	// The breaker still lets the next trial through once the backend recovers
+	state.Store(2)
+	time.Sleep(150 * time.Millisecond)
+	if _, err := client.PostPlayers(context.Background(), d.RosterPath, d.ReqMeta{}); err != nil {
+		t.Errorf("Expected the next trial call to succeed, got %v", err)
+	}
+}
+
+func TestCircuitBreakerIgnoresCancelledCalls(t *testing.T) {
+
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+	client := testClient(server.URL)
+
+	// A call cancelled during the trial neither closes the circuit nor keeps the next trial from going through
+	client.PostPlayers(context.Background(), d.RosterPath, d.ReqMeta{})
+	time.Sleep(150 * time.Millisecond)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := client.PostPlayers(ctx, d.RosterPath, d.ReqMeta{}); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected the call to be cancelled, got %v", err)
+	}
+	client.PostPlayers(context.Background(), d.RosterPath, d.ReqMeta{})
+	if calls.Load() != 4 {
+		t.Errorf("Expected the next trial to reach the backend, got %d calls", calls.Load())
+	}
+}
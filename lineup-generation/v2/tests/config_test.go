@@ -0,0 +1,72 @@
+package tests
+
+import (
+	"testing"
+	u "v2/utils"
+)
+
+func TestOptimizerConfigDefaults(t *testing.T) {
+
+	// A request without an optimizer block should run with the defaults
+	var req u.ReqBody
+	config, err := req.Optimizer.Resolve()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if config != u.DefaultOptimizerConfig() {
+		t.Errorf("Expected default config, got %+v", config)
+	}
+}
+
+func TestOptimizerConfigOverrides(t *testing.T) {
+
+	population_size := 500
+	mutation_rate := 0.0
+	fa_count := 40
+	options := &u.OptimizerOptions{PopulationSize: &population_size, MutationRate: &mutation_rate, FaCount: &fa_count}
+
+	config, err := options.Resolve()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Values above the caps should be clamped and explicit zeros respected
+	if config.PopulationSize != u.MaxPopulationSize {
+		t.Errorf("Population size was not clamped: %d", config.PopulationSize)
+	}
+	if config.MutationRate != 0.0 {
+		t.Errorf("Explicit zero mutation rate was overridden: %v", config.MutationRate)
+	}
+	if config.FaCount != 40 {
+		t.Errorf("FA count is incorrect: %d", config.FaCount)
+	}
+	if config.NumIslands != u.DefaultOptimizerConfig().NumIslands {
+		t.Errorf("Unset field did not keep its default")
+	}
+}
+
+func TestOptimizerConfigInvalid(t *testing.T) {
+
+	negative := -1
+	if _, err := (&u.OptimizerOptions{NumIslands: &negative}).Resolve(); err == nil {
+		t.Errorf("Expected error for negative island count")
+	}
+
+	// The tournament pick has to be inside the tournament
+	size := 3
+	pick := 3
+	if _, err := (&u.OptimizerOptions{TournamentSize: &size, TournamentPick: &pick}).Resolve(); err == nil {
+		t.Errorf("Expected error for tournament pick outside the tournament")
+	}
+
+	mode := "annealing"
+	if _, err := (&u.OptimizerOptions{Mode: &mode}).Resolve(); err == nil {
+		t.Errorf("Expected error for unknown mode")
+	}
+
+	// The exact solver only maximizes points
+	exact, fitness := u.ModeExact, u.FitnessMoveCost
+	if _, err := (&u.OptimizerOptions{Mode: &exact, Fitness: &fitness}).Resolve(); err == nil {
+		t.Errorf("Expected error for the exact mode with a move cost fitness")
+	}
+}
@@ -1,5 +1,14 @@
 package tests
 
+import (
+	"context"
+	"errors"
+	"testing"
+	d "v2/data"
+	l "v2/resources"
+	"v2/team"
+)
+
 // import (
 // 	"testing"
 // 	. "streaming-optimization/data"
@@ -17,4 +26,64 @@ package tests
 // 	if len(players) == 0 {
 // 		t.Errorf("Expected players to be returned, but got 0 players")
 // 	}
-// }
\ No newline at end of file
+// }
+func TestPlayerProviders(t *testing.T) {
+	d.InitSchedule("../static/schedule24-25.json")
+
+	file_provider := d.FileProvider{RosterPath: l.MockRosterPath(), FreeAgentsPath: l.MockFreeAgentsPath()}
+	roster_map, free_agents, err := file_provider.FetchPlayers(context.Background(), d.LeagueInfo{}, 25)
+	if err != nil {
+		t.Fatalf("Failed to read mock players: %v", err)
+	}
+	if len(roster_map) == 0 || len(free_agents) != 25 {
+		t.Errorf("Unexpected player counts: %d rostered, %d free agents", len(roster_map), len(free_agents))
+	}
+
+	// The same players served from memory should produce the same BaseTeam
+	memory_provider := d.MemoryProvider{Roster: roster_map, FreeAgents: free_agents}
+	from_file, err := team.InitBaseTeam(context.Background(), file_provider, &d.ScheduleMap, team.BaseTeamOptions{}, d.LeagueInfo{}, 25, "5", 0, 32.0)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	from_memory, err := team.InitBaseTeam(context.Background(), memory_provider, &d.ScheduleMap, team.BaseTeamOptions{}, d.LeagueInfo{}, 25, "5", 0, 32.0)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if from_file.Score != from_memory.Score || len(from_file.StreamablePlayers) != len(from_memory.StreamablePlayers) {
+		t.Errorf("Providers produced different teams")
+	}
+
+	// A missing file should be an error rather than an empty team
+	missing := d.FileProvider{RosterPath: "missing.json", FreeAgentsPath: l.MockFreeAgentsPath()}
+	if _, err := team.InitBaseTeam(context.Background(), missing, &d.ScheduleMap, team.BaseTeamOptions{}, d.LeagueInfo{}, 25, "5", 0, 32.0); err == nil {
+		t.Errorf("Expected an error for a missing roster file")
+	}
+}
+
+func TestFetchErrors(t *testing.T) {
+
+	// Backend status codes should map to the typed errors
+	cases := []struct {
+		status int
+		body   string
+		want   error
+	}{
+		{401, "", d.ErrBadCredentials},
+		{403, "", d.ErrBadCredentials},
+		{404, `{"detail": "League not found"}`, d.ErrLeagueNotFound},
+		{404, `{"detail": "Team not found"}`, d.ErrTeamNotFound},
+		{500, "", d.ErrBackendUnavailable},
+		{503, "", d.ErrBackendUnavailable},
+	}
+	for _, c := range cases {
+		if err := d.StatusError(c.status, []byte(c.body)); !errors.Is(err, c.want) {
+			t.Errorf("Status %d mapped to %v, expected %v", c.status, err, c.want)
+		}
+	}
+
+	// An empty roster should never make it to the optimizer
+	empty := d.MemoryProvider{Roster: map[string]d.Player{}, FreeAgents: []d.Player{{Name: "FA"}}}
+	if _, err := team.InitBaseTeam(context.Background(), empty, &d.ScheduleMap, team.BaseTeamOptions{}, d.LeagueInfo{TeamName: "Missing"}, 10, "5", 0, 32.0); !errors.Is(err, d.ErrTeamNotFound) {
+		t.Errorf("Expected ErrTeamNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,97 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+	d "v2/data"
+	p "v2/population"
+	u "v2/utils"
+)
+
+func TestFitnessFuncs(t *testing.T) {
+	bt := loadMockTeam("5", 32.0)
+
+	// Find a chromosome that makes at least one move
+	rng := rand.New(rand.NewSource(7))
+	chromosome := p.InitChromosome(bt)
+	for chromosome.TotalAcquisitions == 0 {
+		chromosome = p.InitChromosome(bt)
+		chromosome.Populate(bt, rng)
+	}
+	chromosome.Limit = d.AcquisitionLimit{Period: d.PeriodUnlimited}
+	points := chromosome.Points()
+
+	if score := (p.TruncatedFitness{}).Score(chromosome); score != math.Floor(points) {
+		t.Errorf("Truncated fitness should be %v, got %v", math.Floor(points), score)
+	}
+	if score := (p.PointsFitness{}).Score(chromosome); score != points {
+		t.Errorf("Points fitness should be %v, got %v", points, score)
+	}
+	if score := (p.MoveCostFitness{Cost: 3}).Score(chromosome); math.Abs(score-(points-3*float64(chromosome.TotalAcquisitions))) > 1e-9 {
+		t.Errorf("Move cost fitness didn't charge for %d moves: %v", chromosome.TotalAcquisitions, score)
+	}
+
+	// Taking on risk should only ever cost points
+	if score := (p.MeanVarianceFitness{RiskAversion: 0, GameCV: p.DefaultGameCV}).Score(chromosome); score != points {
+		t.Errorf("Mean-variance fitness without risk aversion should be the points, got %v", score)
+	}
+	if score := (p.MeanVarianceFitness{RiskAversion: 0.01, GameCV: p.DefaultGameCV}).Score(chromosome); score >= points {
+		t.Errorf("Mean-variance fitness should be below the points, got %v", score)
+	}
+
+	// Every fitness function rules out breaking a hard limit
+	chromosome.Limit = d.AcquisitionLimit{Period: d.PeriodMatchup, Limit: 0, Hard: true}
+	for _, fitness := range []p.FitnessFunc{p.TruncatedFitness{}, p.PointsFitness{}, p.MoveCostFitness{Cost: 3}, p.MeanVarianceFitness{RiskAversion: 0.01}} {
+		if score := fitness.Score(chromosome); score != -math.MaxFloat64 {
+			t.Errorf("%T should rule out an infeasible chromosome, got %v", fitness, score)
+		}
+	}
+
+	// Charging more for moves than they bring in makes the score negative, and going over the limit still lowers it
+	costly := p.MoveCostFitness{Cost: u.MaxMoveCost}
+	chromosome.Limit = d.AcquisitionLimit{Period: d.PeriodUnlimited}
+	within := costly.Score(chromosome)
+	if within >= 0 {
+		t.Fatalf("Expected a negative score, got %v", within)
+	}
+	chromosome.Limit = d.AcquisitionLimit{Period: d.PeriodMatchup, Limit: 0}
+	if over := costly.Score(chromosome); over >= within {
+		t.Errorf("Going over a soft limit raised a negative score from %v to %v", within, over)
+	}
+
+	// A chromosome that breaks a hard limit never beats one with a negative score that doesn't
+	chromosome.Limit = d.AcquisitionLimit{Period: d.PeriodMatchup, Limit: 0, Hard: true}
+	if over := costly.Score(chromosome); over >= within {
+		t.Errorf("Breaking a hard limit scored %v, not below the feasible %v", over, within)
+	}
+}
+
+func TestPopulationFitness(t *testing.T) {
+	bt := loadMockTeam("5", 32.0)
+
+	fitness := u.FitnessPoints
+	config, err := (&u.OptimizerOptions{Fitness: &fitness}).Resolve()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	ev, _ := p.InitPopulation(context.Background(), bt, config, rand.New(rand.NewSource(7)))
+	if _, ok := ev.Fitness.(p.PointsFitness); !ok {
+		t.Fatalf("Expected the points fitness, got %T", ev.Fitness)
+	}
+
+	// Swapping the fitness function rescores the whole population
+	ev.SetFitness(p.MoveCostFitness{Cost: 1000})
+	for _, chromosome := range ev.Population {
+		if chromosome.TotalAcquisitions > 0 && chromosome.IsFeasible() && chromosome.FitnessScore >= 0 {
+			t.Errorf("Chromosome with %d moves wasn't charged for them", chromosome.TotalAcquisitions)
+		}
+	}
+
+	unknown := "wins"
+	if _, err := (&u.OptimizerOptions{Fitness: &unknown}).Resolve(); !errors.Is(err, u.ErrInvalidOptimizer) {
+		t.Errorf("Expected ErrInvalidOptimizer for an unknown fitness, got %v", err)
+	}
+}
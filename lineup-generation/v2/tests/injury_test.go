@@ -0,0 +1,90 @@
+package tests
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+	d "v2/data"
+	l "v2/resources"
+	p "v2/population"
+	"v2/team"
+)
+
+// Function to build the mock team for week 5 with Desmond Bane on IR until Thursday and an injured free agent coming back Friday
+func loadInjuredTeam(t *testing.T) *team.BaseTeam {
+	d.InitSchedule("../static/schedule24-25.json")
+
+	roster_map := l.LoadRosterMap(l.MockRosterPath())
+	bane := roster_map["Desmond Bane"]
+	bane.OnIR, bane.InjuryStatus, bane.ReturnDate = true, "OUT", "2024-11-21"
+	roster_map["Desmond Bane"] = bane
+
+	free_agents := []d.Player{{Name: "Injured FA", AvgPoints: 30.0, Team: "MEM", ValidPositions: []string{"SG", "G", "UT"}, Injured: true, ReturnDate: "2024-11-22"}}
+	provider := d.MemoryProvider{Roster: roster_map, FreeAgents: free_agents}
+	bt, err := team.InitBaseTeam(context.Background(), provider, &d.ScheduleMap, team.BaseTeamOptions{}, d.LeagueInfo{}, 1, "5", 0, 32.0)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return bt
+}
+
+func TestIRActivation(t *testing.T) {
+	bt := loadInjuredTeam(t)
+
+	if day, ok := bt.ReturnDays["Desmond Bane"]; !ok || day != 3 {
+		t.Fatalf("Expected Desmond Bane back on day 3, got %d %v", day, ok)
+	}
+	if len(bt.Activations) != 1 || bt.Activations[0].Day != 3 {
+		t.Fatalf("Expected one activation on day 3, got %+v", bt.Activations)
+	}
+
+	// The roster is full, so bringing him back costs a streamer
+	if bt.ForcedDrops[3] != 1 {
+		t.Errorf("Expected a forced drop on day 3, got %v", bt.ForcedDrops)
+	}
+
+	// He shouldn't start before he's back, and should take the IR slot until then
+	for day, lineup := range bt.OptimalSlotting {
+		for _, player := range lineup {
+			if player.Name == "Desmond Bane" && day < 3 {
+				t.Errorf("Desmond Bane slotted on day %d before his return", day)
+			}
+			if player.Name == "Tobias Harris" {
+				t.Errorf("Tobias Harris slotted on day %d with no return date", day)
+			}
+		}
+		if ir := bt.IRSlotting(day); (day < 3) != (ir["IR"].Name == "Desmond Bane") {
+			t.Errorf("IR slot on day %d is incorrect: %+v", day, ir)
+		}
+	}
+
+	// An injured free agent can't be picked up to play before he's back
+	free_agent := bt.FreeAgents[0]
+	for day := 0; day <= 6; day++ {
+		if bt.CanPlay(free_agent, day) && day < 4 {
+			t.Errorf("Injured free agent can play on day %d", day)
+		}
+	}
+}
+
+func TestForcedDrops(t *testing.T) {
+	bt := loadInjuredTeam(t)
+
+	// With the only free agent out for the week, the only change to the streamers is the forced drop
+	delete(bt.ReturnDays, "Injured FA")
+	chromosome := p.InitChromosome(bt)
+	chromosome.Populate(bt, rand.New(rand.NewSource(7)))
+
+	if len(chromosome.CurStreamers) != len(bt.StreamablePlayers)-1 {
+		t.Fatalf("Expected one streamer to be dropped, %d left", len(chromosome.CurStreamers))
+	}
+	dropped := chromosome.Genes[3].DroppedPlayers
+	if len(dropped) != 1 || dropped[0].Name != "Vince Williams Jr." {
+		t.Fatalf("Expected the worst streamer to be dropped on day 3, got %+v", dropped)
+	}
+	for i, gene := range chromosome.Genes {
+		if gene.IsPlayerInGene(dropped[0]) != (i < 3) {
+			t.Errorf("Dropped streamer is in the wrong genes on day %d", gene.Day)
+		}
+	}
+}
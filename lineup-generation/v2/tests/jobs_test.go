@@ -0,0 +1,125 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"v2/jobs"
+	p "v2/population"
+	u "v2/utils"
+)
+
+// Function to poll a job until it leaves the queued and running states
+func waitForJob(t *testing.T, m *jobs.Manager, id string) jobs.Snapshot {
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		job, ok := m.Get(id)
+		if !ok {
+			t.Fatalf("Job %s disappeared", id)
+		}
+		if job.Status != jobs.StatusQueued && job.Status != jobs.StatusRunning {
+			return job
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("Job %s did not finish", id)
+	return jobs.Snapshot{}
+}
+
+func TestJobCompletes(t *testing.T) {
+	m := jobs.NewManager(1, 4, time.Minute, func(ctx context.Context, req u.ReqBody, on_progress p.ProgressFunc) (u.Response, error) {
+		for i := 1; i <= 3; i++ {
+			on_progress(p.Progress{Island: 0, Generation: i, BestFitness: float64(10 * i)})
+		}
+		return u.Response{Week: req.Week}, nil
+	})
+	defer m.Close()
+
+	job, err := m.Submit(u.ReqBody{Week: "4"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if job.ID == "" || job.Status != jobs.StatusQueued {
+		t.Errorf("New job is not queued: %+v", job)
+	}
+
+	job = waitForJob(t, m, job.ID)
+	if job.Status != jobs.StatusDone || job.Result == nil || job.Result.Week != "4" {
+		t.Fatalf("Job did not finish with a result: %+v", job)
+	}
+	if job.Generation != 3 || job.BestFitness != 30 {
+		t.Errorf("Progress was not recorded: %+v", job)
+	}
+}
+
+func TestJobFailsAndCancels(t *testing.T) {
+	release := make(chan struct{})
+	m := jobs.NewManager(1, 4, time.Minute, func(ctx context.Context, req u.ReqBody, on_progress p.ProgressFunc) (u.Response, error) {
+		if req.Week == "fail" {
+			return u.Response{}, errors.New("boom")
+		}
+		select {
+		case <-ctx.Done():
+		case <-release:
+		}
+		return u.Response{}, nil
+	})
+	defer m.Close()
+
+	// A failing run should surface its error
+	failed, _ := m.Submit(u.ReqBody{Week: "fail"})
+	if job := waitForJob(t, m, failed.ID); job.Status != jobs.StatusFailed || job.Error != "boom" {
+		t.Errorf("Job did not fail: %+v", job)
+	}
+
+	// A running job and a queued job should both be cancellable
+	running, _ := m.Submit(u.ReqBody{})
+	queued, _ := m.Submit(u.ReqBody{})
+	for _, id := range []string{running.ID, queued.ID} {
+		if job, ok := m.Cancel(id); !ok || job.Status != jobs.StatusCancelled {
+			t.Errorf("Job was not cancelled: %+v", job)
+		}
+	}
+	close(release)
+
+	if _, ok := m.Cancel("missing"); ok {
+		t.Errorf("Cancelled a job that does not exist")
+	}
+}
+
+func TestJobQueueFullAndExpiry(t *testing.T) {
+	release := make(chan struct{})
+	m := jobs.NewManager(1, 1, time.Minute, func(ctx context.Context, req u.ReqBody, on_progress p.ProgressFunc) (u.Response, error) {
+		<-release
+		return u.Response{}, nil
+	})
+	defer m.Close()
+
+	// One job occupies the worker and one fills the queue
+	first, _ := m.Submit(u.ReqBody{})
+	for {
+		if job, _ := m.Get(first.ID); job.Status == jobs.StatusRunning {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	second, _ := m.Submit(u.ReqBody{})
+	if _, err := m.Submit(u.ReqBody{}); !errors.Is(err, jobs.ErrQueueFull) {
+		t.Errorf("Expected a full queue, got %v", err)
+	}
+	close(release)
+	waitForJob(t, m, first.ID)
+	waitForJob(t, m, second.ID)
+
+	// Finished jobs should only be removed once they outlive the TTL
+	if removed := m.Expire(time.Now()); removed != 0 {
+		t.Errorf("Expired %d jobs before the TTL", removed)
+	}
+	if removed := m.Expire(time.Now().Add(2 * time.Minute)); removed != 2 {
+		t.Errorf("Expected 2 expired jobs, got %d", removed)
+	}
+	if _, ok := m.Get(first.ID); ok {
+		t.Errorf("Expired job is still available")
+	}
+}
@@ -0,0 +1,94 @@
+package tests
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+	d "v2/data"
+	p "v2/population"
+	u "v2/utils"
+)
+
+func TestAcquisitionLimitExcess(t *testing.T) {
+
+	// Two-week matchup with one move already made, optimizing from day 3
+	daily := []int{1, 0, 2, 1, 0, 0, 0, 2, 1, 0, 0} // Days 3-13
+	cases := []struct {
+		name  string
+		limit d.AcquisitionLimit
+		want  int
+	}{
+		{"matchup", d.AcquisitionLimit{Period: d.PeriodMatchup, Limit: 7}, 1},
+		{"week", d.AcquisitionLimit{Period: d.PeriodWeek, Limit: 4}, 1},
+		{"unlimited", d.AcquisitionLimit{Period: d.PeriodUnlimited}, 0},
+		{"daily cap", d.AcquisitionLimit{Period: d.PeriodUnlimited, DailyCap: 1}, 2},
+		{"week and daily cap", d.AcquisitionLimit{Period: d.PeriodWeek, Limit: 4, DailyCap: 1}, 3},
+	}
+	for _, c := range cases {
+		if excess := c.limit.Excess(3, daily, 1); excess != c.want {
+			t.Errorf("%s: expected %d excess acquisitions, got %d", c.name, c.want, excess)
+		}
+	}
+
+	// Allowances account for earlier days and the week each day falls in
+	so_far := []int{1, 0, 2, 0, 0, 0, 0, 0, 0, 0, 0}
+	week := d.AcquisitionLimit{Period: d.PeriodWeek, Limit: 4, DailyCap: 2}
+	if allowance := week.Allowance(3, so_far, 1, 3); allowance != 0 {
+		t.Errorf("Expected no moves left in the first week, got %d", allowance)
+	}
+	if allowance := week.Allowance(3, so_far, 1, 4); allowance != 2 {
+		t.Errorf("Expected the daily cap of 2 in the second week, got %d", allowance)
+	}
+
+	invalid := []d.AcquisitionLimit{{Period: "season"}, {Period: d.PeriodWeek, Limit: -1}, {Period: d.PeriodMatchup, DailyCap: -2}}
+	for _, limit := range invalid {
+		if limit.Validate() == nil {
+			t.Errorf("Expected %+v to be invalid", limit)
+		}
+	}
+}
+
+func TestHardAcquisitionLimit(t *testing.T) {
+	bt := loadMockTeam("5", 32.0)
+	bt.AcquisitionLimit = d.AcquisitionLimit{Period: d.PeriodMatchup, Limit: 2, DailyCap: 1, Hard: true}
+
+	config := u.DefaultOptimizerConfig()
+	ev, err := p.InitPopulation(context.Background(), bt, config, rand.New(rand.NewSource(11)))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for i := 0; i < 5; i++ {
+		if err := ev.Evolve(context.Background(), bt); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	// No chromosome should ever break the limit in hard mode
+	for _, chromosome := range ev.Population {
+		if !chromosome.IsFeasible() || chromosome.TotalAcquisitions > 2 {
+			t.Fatalf("Chromosome makes %d acquisitions under a hard limit of 2", chromosome.TotalAcquisitions)
+		}
+		for _, gene := range chromosome.Genes {
+			if gene.Acquisitions > 1 {
+				t.Fatalf("Day %d makes %d acquisitions under a daily cap of 1", gene.Day, gene.Acquisitions)
+			}
+		}
+	}
+	if best := ev.BestValid(); best == nil || best != ev.Population[ev.NumChromosomes-1] {
+		t.Errorf("Expected the fittest chromosome to be valid")
+	}
+}
+
+func TestSoftAcquisitionLimit(t *testing.T) {
+	bt := loadMockTeam("5", 32.0)
+	bt.AcquisitionLimit = d.AcquisitionLimit{Period: d.PeriodMatchup, Limit: 0}
+
+	// With no moves allowed every acquisition is penalized, and only chromosomes without any are valid
+	ev, err := p.InitPopulation(context.Background(), bt, u.DefaultOptimizerConfig(), rand.New(rand.NewSource(11)))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if best := ev.BestValid(); best != nil && best.TotalAcquisitions != 0 {
+		t.Errorf("Valid chromosome makes %d acquisitions with none allowed", best.TotalAcquisitions)
+	}
+}
@@ -0,0 +1,99 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	d "v2/data"
+	l "v2/resources"
+	p "v2/population"
+	"v2/team"
+)
+
+func TestOpponentProjection(t *testing.T) {
+	d.InitSchedule("../static/schedule24-25.json")
+
+	provider := d.MemoryProvider{Roster: l.LoadRosterMap(l.MockRosterPath()), FreeAgents: l.LoadFreeAgents(l.MockFreeAgentsPath())}
+	opponent, err := team.InitOpponent(context.Background(), provider, &d.ScheduleMap, team.BaseTeamOptions{}, d.LeagueInfo{TeamName: "Opponent"}, "5", 0)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Nobody on the opponent's roster is streamed, so everyone who is healthy competes for a spot
+	if len(opponent.StreamablePlayers) != 0 {
+		t.Errorf("Opponent shouldn't have streamers, got %d", len(opponent.StreamablePlayers))
+	}
+
+	mean, variance := opponent.ProjectTotal(p.DefaultGameCV)
+	if math.Abs(mean-float64(opponent.Score)) > 1 {
+		t.Errorf("Projected total %v doesn't match the optimal score %d", mean, opponent.Score)
+	}
+	if variance <= 0 {
+		t.Errorf("Expected a positive variance, got %v", variance)
+	}
+	if _, calm := opponent.ProjectTotal(0); calm != 0 {
+		t.Errorf("Healthy players with no spread should have no variance, got %v", calm)
+	}
+}
+
+func TestOpponentRosterOnly(t *testing.T) {
+	d.InitSchedule("../static/schedule24-25.json")
+
+	// We don't stream for the opponent, so their free agents are never asked for
+	var free_agent_calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == d.FreeAgentsPath {
+			free_agent_calls.Add(1)
+		}
+		json.NewEncoder(w).Encode(l.LoadFreeAgents(l.MockFreeAgentsPath())[:10])
+	}))
+	defer server.Close()
+
+	provider := d.BackendProvider{Client: testClient(server.URL)}
+	opponent, err := team.InitOpponent(context.Background(), provider, &d.ScheduleMap, team.BaseTeamOptions{}, d.LeagueInfo{TeamName: "Opponent"}, "5", 0)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(opponent.RosterMap) != 10 || len(opponent.FreeAgents) != 0 {
+		t.Errorf("Expected 10 rostered players and no free agents, got %d and %d", len(opponent.RosterMap), len(opponent.FreeAgents))
+	}
+	if free_agent_calls.Load() != 0 {
+		t.Errorf("Expected no calls for free agents, got %d", free_agent_calls.Load())
+	}
+}
+
+func TestWinProbabilityFitness(t *testing.T) {
+	bt := loadMockTeam("5", 32.0)
+
+	chromosome := p.InitChromosome(bt)
+	for _, gene := range chromosome.Genes {
+		gene.InsertStreamablePlayers(bt)
+	}
+	points := chromosome.Points()
+
+	// Level on projections is a coin flip, and a big lead is all but won
+	even := &p.WinProbabilityFitness{Lead: -points, BaseVariance: 400, GameCV: p.DefaultGameCV}
+	if probability := even.WinProbability(chromosome); math.Abs(probability-0.5) > 1e-9 {
+		t.Errorf("Expected an even matchup, got %v", probability)
+	}
+	ahead := &p.WinProbabilityFitness{Lead: 500, BaseVariance: 400, GameCV: p.DefaultGameCV}
+	if probability := ahead.Score(chromosome); probability < 0.99 {
+		t.Errorf("Expected a big lead to be almost certain, got %v", probability)
+	}
+
+	// Trailing teams want their streamers to swing more and leading teams want them to swing less
+	for _, c := range []struct {
+		lead  float64
+		risky bool
+	}{{-points - 50, true}, {-points + 50, false}} {
+		calm := (&p.WinProbabilityFitness{Lead: c.lead, BaseVariance: 400, GameCV: 0.1}).WinProbability(chromosome)
+		wild := (&p.WinProbabilityFitness{Lead: c.lead, BaseVariance: 400, GameCV: 0.6}).WinProbability(chromosome)
+		if (wild > calm) != c.risky {
+			t.Errorf("With a lead of %v, swingier streamers gave %v against %v", c.lead+points, wild, calm)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"testing"
+	d "v2/data"
+	p "v2/population"
+	l "v2/resources"
+	"v2/team"
+	u "v2/utils"
+)
+
+func TestMidWeekOptimization(t *testing.T) {
+	d.InitSchedule("../static/schedule24-25.json")
+
+	// Re-optimize week 5 (days 0-6) from Thursday with two moves already made
+	provider := d.FileProvider{RosterPath: l.MockRosterPath(), FreeAgentsPath: l.MockFreeAgentsPath()}
+	bt, err := team.InitBaseTeam(context.Background(), provider, &d.ScheduleMap, team.BaseTeamOptions{}, d.LeagueInfo{}, 0, "5", 3, 32.0)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	bt.AcquisitionsUsed = 2
+
+	// Only the remaining days are slotted and scored
+	if len(bt.OptimalSlotting) != 4 || len(bt.UnusedPositions) != 4 {
+		t.Errorf("Expected 4 days of slotting, got %d and %d", len(bt.OptimalSlotting), len(bt.UnusedPositions))
+	}
+	if _, ok := bt.OptimalSlotting[2]; ok {
+		t.Errorf("Day 2 has already been played but was slotted")
+	}
+	full_week := loadMockTeam("5", 32.0)
+	if bt.Score >= full_week.Score {
+		t.Errorf("Remaining days scored %d, not less than the full week's %d", bt.Score, full_week.Score)
+	}
+
+	// Genes only cover the remaining days and the acquisitions already made come off the limit
+	config := u.DefaultOptimizerConfig()
+	config.NumIslands = 1
+	ev, err := p.InitPopulation(context.Background(), bt, config, rand.New(rand.NewSource(3)))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		if err := ev.Evolve(context.Background(), bt); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	for _, chromosome := range ev.Population {
+		if len(chromosome.Genes) != 4 || chromosome.AcquisitionsUsed != 2 {
+			t.Fatalf("Chromosome has %d genes and %d acquisitions used", len(chromosome.Genes), chromosome.AcquisitionsUsed)
+		}
+		for i, gene := range chromosome.Genes {
+			if gene.Day != 3+i {
+				t.Fatalf("Gene %d is for day %d", i, gene.Day)
+			}
+		}
+	}
+
+	best := ev.BestValid()
+	if best != nil && best.TotalAcquisitions > 5 {
+		t.Errorf("Best chromosome makes %d moves with 5 left", best.TotalAcquisitions)
+	}
+}
+
+func TestMidWeekInvalidStartDay(t *testing.T) {
+	d.InitSchedule("../static/schedule24-25.json")
+
+	provider := d.FileProvider{RosterPath: l.MockRosterPath(), FreeAgentsPath: l.MockFreeAgentsPath()}
+	for _, start_day := range []int{-1, 7} {
+		if _, err := team.InitBaseTeam(context.Background(), provider, &d.ScheduleMap, team.BaseTeamOptions{}, d.LeagueInfo{}, 0, "5", start_day, 32.0); !errors.Is(err, d.ErrInvalidStartDay) {
+			t.Errorf("Expected ErrInvalidStartDay for day %d, got %v", start_day, err)
+		}
+	}
+}
@@ -1,7 +1,9 @@
 package tests
 
 import (
+	"context"
 	"fmt"
+	"encoding/json"
 	"math/rand"
 	"time"
 	"sync"
@@ -9,6 +11,7 @@ import (
 	"v2/team"
 	d "v2/data"
 	p "v2/population"
+	u "v2/utils"
 )
 
 func TestOptimizeStreaming(t *testing.T) {
@@ -17,7 +20,11 @@ func TestOptimizeStreaming(t *testing.T) {
 
 	// bt := team.InitBaseTeamMock("16", 34.0)
 	week := "9"
-	bt := team.InitBaseTeam(424233486, "", "", "James's Scary Team", 2024, 100, week, 31.0)
+	league := d.LeagueInfo{LeagueId: 424233486, EspnS2: "", Swid: "", TeamName: "James's Scary Team", Year: 2024}
+	bt, err := team.InitBaseTeam(context.Background(), d.BackendProvider{}, &d.ScheduleMap, team.BaseTeamOptions{}, league, 100, week, 0, 31.0)
+	if err != nil {
+		t.Fatalf("Failed to initialize BaseTeam: %v", err)
+	}
 
 	// // Create new populations
 	// ev1 := p.InitPopulation(bt, 25)
@@ -96,8 +103,10 @@ func TestOptimizeStreaming(t *testing.T) {
 	// fmt.Println("Time to run InitPopulation: ", elapsed)
 
 	// Create new populations
-	ev1 := p.InitPopulation(bt, 20)
-	ev2 := p.InitPopulation(bt, 20)
+	config := u.DefaultOptimizerConfig()
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+	ev1, _ := p.InitPopulation(context.Background(), bt, config, rng)
+	ev2, _ := p.InitPopulation(context.Background(), bt, config, rng)
 
 	// Evolve the populations concurrently
 	var wg sync.WaitGroup
@@ -105,13 +114,13 @@ func TestOptimizeStreaming(t *testing.T) {
 	go func() {
 		defer wg.Done()
 		for i := 0; i < 10; i++ {
-			ev1.Evolve(bt)
+			ev1.Evolve(context.Background(), bt)
 		}
 	}()
 	go func() {
 		defer wg.Done()
 		for i := 0; i < 10; i++ {
-			ev2.Evolve(bt)
+			ev2.Evolve(context.Background(), bt)
 		}
 	}()
 	wg.Wait()
@@ -123,7 +132,7 @@ func TestOptimizeStreaming(t *testing.T) {
 	
 	// Evolve the combined population
 	for i := 0; i < 10; i++ {
-		ev1.Evolve(bt)
+		ev1.Evolve(context.Background(), bt)
 	}
 
 	ev1.SortByFitness()
@@ -145,7 +154,7 @@ func TestOptimizeStreaming(t *testing.T) {
 	base_chromosome.ScoreFitness()
 
 	// // Print the best chromosome
-	fmt.Println(bt.Score + best_chromosome.FitnessScore, "vs", bt.Score + base_chromosome.FitnessScore, "diff", best_chromosome.FitnessScore - base_chromosome.FitnessScore)
+	fmt.Println(float64(bt.Score) + best_chromosome.FitnessScore, "vs", float64(bt.Score) + base_chromosome.FitnessScore, "diff", best_chromosome.FitnessScore - base_chromosome.FitnessScore)
 	best_chromosome.Print()
 	elapsed := time.Since(start)
 	fmt.Println("Time to run algorithm: ", elapsed)
@@ -159,11 +168,13 @@ func TestEvolve(t *testing.T) {
 	bt := team.InitBaseTeamMock("19", 32.0)
 
 	// Create the EvolutionManager
-	ev := p.InitPopulation(bt, 50)
+	config := u.DefaultOptimizerConfig()
+	config.PopulationSize = 50
+	ev, _ := p.InitPopulation(context.Background(), bt, config, rand.New(rand.NewSource(time.Now().UnixNano())))
 
 	// Evolve the population
 	for i := 0; i < 100; i++ {
-		ev.Evolve(bt)
+		ev.Evolve(context.Background(), bt)
 
 		// Make sure there are no duplicate players in each gene's NewPlayers
 		for _, chromosome := range ev.Population {
@@ -324,4 +335,65 @@ func TestMutate(t *testing.T) {
 			}
 		}
 	}
-}
\ No newline at end of file
+}
+func TestSeededEvolutionIsDeterministic(t *testing.T) {
+	bt := loadMockTeam("5", 32.0)
+
+	// Run the same seeded evolution twice and return the serialized best chromosome
+	run := func(seed int64) string {
+		config := u.DefaultOptimizerConfig()
+		rng := rand.New(rand.NewSource(seed))
+		ev, _ := p.InitPopulation(context.Background(), bt, config, rng)
+		for i := 0; i < 5; i++ {
+			ev.Evolve(context.Background(), bt)
+		}
+		ev.SortByFitness()
+
+		json_data, err := json.Marshal(ev.Population[ev.NumChromosomes-1].Slim())
+		if err != nil {
+			t.Fatalf("Failed to encode chromosome: %v", err)
+		}
+		return string(json_data)
+	}
+
+	if run(42) != run(42) {
+		t.Errorf("Same seed produced different lineups")
+	}
+}
+
+func TestEvolveStopsWhenCancelled(t *testing.T) {
+	bt := loadMockTeam("5", 32.0)
+	config := u.DefaultOptimizerConfig()
+
+	// A population started with a dead context should come back empty with the context's error
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	ev, err := p.InitPopulation(cancelled, bt, config, rand.New(rand.NewSource(1)))
+	if err == nil || ev.NumChromosomes != 0 {
+		t.Errorf("Expected an empty population and an error, got %d chromosomes and %v", ev.NumChromosomes, err)
+	}
+
+	// Cancelling an evolution should keep the previous generation intact
+	ev, err = p.InitPopulation(context.Background(), bt, config, rand.New(rand.NewSource(1)))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	before := make([]*p.Chromosome, ev.NumChromosomes)
+	copy(before, ev.Population)
+	if err := ev.Evolve(cancelled, bt); err == nil {
+		t.Errorf("Expected an error from a cancelled evolution")
+	}
+	for i := range before {
+		if ev.Population[i] != before[i] {
+			t.Fatalf("Cancelled evolution replaced the population")
+		}
+	}
+	if ev.Generation != 0 {
+		t.Errorf("Cancelled evolution counted a generation")
+	}
+
+	// The best valid chromosome should still be available afterwards
+	if best := ev.BestValid(); best == nil {
+		t.Errorf("No valid chromosome found after cancellation")
+	}
+}
@@ -0,0 +1,93 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+	d "v2/data"
+	l "v2/resources"
+	p "v2/population"
+	"v2/team"
+	u "v2/utils"
+)
+
+func TestRecencyWeighted(t *testing.T) {
+	date := time.Date(2024, 11, 18, 0, 0, 0, 0, time.UTC)
+	weights := d.RecencyWeighted{Season: 1, Last7: 1}
+
+	// A player with every window blends them by weight
+	player := d.Player{AvgPoints: 20, AvgLast7: 40, AvgLast15: 30, AvgLast30: 25}
+	if points := weights.Project(player, date); points != 30 {
+		t.Errorf("Expected a blend of 30, got %v", points)
+	}
+
+	// Windows without an average are left out of the blend
+	if points := (d.RecencyWeighted{Season: 1, Last15: 1, Last7: 2}).Project(d.Player{AvgPoints: 20, AvgLast15: 32}, date); points != 26 {
+		t.Errorf("Expected missing windows to be skipped, got %v", points)
+	}
+	if points := weights.Project(d.Player{AvgPoints: 20}, date); points != 20 {
+		t.Errorf("Expected the season average with no recent form, got %v", points)
+	}
+
+	for _, bad := range []d.RecencyWeighted{{}, {Season: 1, Last7: -1}} {
+		if err := bad.Validate(); !errors.Is(err, d.ErrInvalidProjection) {
+			t.Errorf("Expected ErrInvalidProjection for %+v, got %v", bad, err)
+		}
+	}
+}
+
+func TestFileProjector(t *testing.T) {
+	d.InitSchedule("../static/schedule24-25.json")
+
+	// Project Vince Williams Jr. to go off on the Monday of week 5
+	path := filepath.Join(t.TempDir(), "projections.json")
+	if err := os.WriteFile(path, []byte(`{"Vince Williams Jr.": {"2024-11-18": 80.0}}`), 0o644); err != nil {
+		t.Fatalf("Failed to write projections: %v", err)
+	}
+	projector, err := d.LoadFileProjector(path)
+	if err != nil {
+		t.Fatalf("Failed to load projections: %v", err)
+	}
+	if _, err := d.LoadFileProjector(filepath.Join(t.TempDir(), "missing.json")); !errors.Is(err, d.ErrInvalidProjection) {
+		t.Errorf("Expected ErrInvalidProjection for a missing file, got %v", err)
+	}
+
+	provider := d.MemoryProvider{Roster: l.LoadRosterMap(l.MockRosterPath()), FreeAgents: l.LoadFreeAgents(l.MockFreeAgentsPath())}
+	bt, err := team.InitBaseTeam(context.Background(), provider, &d.ScheduleMap, team.BaseTeamOptions{Projector: projector}, d.LeagueInfo{}, 50, "5", 0, 32.0)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	vince := bt.RosterMap["Vince Williams Jr."]
+	if bt.ProjectedPoints(vince, 0) != 80 || bt.ProjectedPoints(vince, 1) != vince.AvgPoints {
+		t.Errorf("Projections are incorrect: %v", bt.Projections)
+	}
+
+	// Genes score him on the projection for their own day
+	chromosome := p.InitChromosome(bt)
+	for _, gene := range chromosome.Genes {
+		gene.InsertStreamablePlayers(bt)
+	}
+	chromosome.ScoreFitness()
+	total := 0.0
+	for _, gene := range chromosome.Genes {
+		for _, player := range gene.Roster {
+			total += bt.ProjectedPoints(player, gene.Day)
+		}
+	}
+	if math.Abs(chromosome.FitnessScore-total) > 1 {
+		t.Errorf("Fitness should be %v, got %v", total, chromosome.FitnessScore)
+	}
+
+	// The bench is ordered by the day's projections rather than the season average
+	bench := u.Bench{Projections: map[string]float64{"A": 50}}
+	bench.AddPlayer(d.Player{Name: "A", AvgPoints: 10})
+	bench.AddPlayer(d.Player{Name: "B", AvgPoints: 30})
+	if bench.Players[0].Name != "B" {
+		t.Errorf("Expected B to be the worst bench player on the day, got %s", bench.Players[0].Name)
+	}
+}
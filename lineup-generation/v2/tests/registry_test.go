@@ -0,0 +1,72 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	d "v2/data"
+	p "v2/population"
+	l "v2/resources"
+	"v2/team"
+)
+
+func TestSeasonYear(t *testing.T) {
+
+	cases := map[string]int{"schedule24-25.json": 2025, "schedule23-24.json": 2024, "schedule99-00.json": 2100}
+	for name, want := range cases {
+		if year, ok := d.SeasonYear(name); !ok || year != want {
+			t.Errorf("%s gave %d, expected %d", name, year, want)
+		}
+	}
+	for _, name := range []string{"schedule.json", "schedule24-26.json", "calendar24-25.json"} {
+		if _, ok := d.SeasonYear(name); ok {
+			t.Errorf("%s should not be a schedule file", name)
+		}
+	}
+}
+
+func TestScheduleRegistry(t *testing.T) {
+
+	registry := d.NewScheduleRegistry()
+	if err := registry.LoadDir("../static"); err != nil {
+		t.Fatalf("Failed to load schedules: %v", err)
+	}
+	if years := registry.Years(); !reflect.DeepEqual(years, []int{2024, 2025}) {
+		t.Errorf("Unexpected seasons %v", years)
+	}
+
+	// Week 17 was the All-Star matchup in 2023-24 but not in 2024-25
+	last_season, err := registry.Season(2024)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	this_season, err := registry.Season(2025)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if last_season.GetGameSpan("17") != 13 || this_season.GetGameSpan("17") != 6 {
+		t.Errorf("Seasons were mixed up")
+	}
+
+	// No year means the latest season
+	if latest, _ := registry.Season(0); latest != this_season {
+		t.Errorf("Expected the latest season for year 0")
+	}
+	if _, err := registry.Season(2019); !errors.Is(err, d.ErrSeasonNotFound) {
+		t.Errorf("Expected ErrSeasonNotFound, got %v", err)
+	}
+
+	// Teams and chromosomes use the schedule they were built with rather than ScheduleMap
+	provider := d.FileProvider{RosterPath: l.MockRosterPath(), FreeAgentsPath: l.MockFreeAgentsPath()}
+	bt, err := team.InitBaseTeam(context.Background(), provider, last_season, team.BaseTeamOptions{}, d.LeagueInfo{}, 25, "17", 0, 32.0)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(bt.OptimalSlotting) != 14 {
+		t.Errorf("Expected 14 days of slotting, got %d", len(bt.OptimalSlotting))
+	}
+	if chromosome := p.InitChromosome(bt); len(chromosome.Genes) != 14 || chromosome.Limit.Limit != 14 {
+		t.Errorf("Chromosome has %d genes and %d acquisitions", len(chromosome.Genes), chromosome.Limit.Limit)
+	}
+}
@@ -0,0 +1,125 @@
+package tests
+
+import (
+	"context"
+	"math/rand"
+	"reflect"
+	"testing"
+	d "v2/data"
+	p "v2/population"
+	l "v2/resources"
+	"v2/team"
+	u "v2/utils"
+)
+
+func TestDefaultRosterTemplate(t *testing.T) {
+	template := d.DefaultRosterTemplate()
+	if err := template.Validate(); err != nil {
+		t.Fatalf("Default template is invalid: %v", err)
+	}
+
+	// The default layout should fill slots in the order the optimizer always has
+	order := []string{"PG", "SG", "SF", "PF", "G", "F", "C", "UT1", "UT2", "UT3"}
+	if got := template.SlotOrder(); !reflect.DeepEqual(got, order) {
+		t.Errorf("Unexpected slot order %v", got)
+	}
+
+	// And reach the same max scores as the old breakpoints
+	legacy := []int{0, 5, 10, 15, 20, 24, 28, 31, 33, 35, 37}
+	for n, want := range legacy {
+		if got := template.MaxScore(n); got != want {
+			t.Errorf("Max score for %d players is %d, expected %d", n, got, want)
+		}
+	}
+
+	// Positions ESPN lists are mapped onto the template's slots
+	if got := template.EligibleSlots([]string{"PG", "SG", "G", "UT1", "UT2", "UT3"}); !reflect.DeepEqual(got, []string{"PG", "SG", "G", "UT1", "UT2", "UT3"}) {
+		t.Errorf("Unexpected eligible slots %v", got)
+	}
+}
+
+// Function to get a layout with no G or F slots, 2 UT and 4 bench spots
+func smallRosterTemplate() *d.RosterTemplate {
+	return &d.RosterTemplate{Slots: []d.RosterSlot{
+		{Name: "PG", Eligible: []string{"PG"}, Count: 1, Priority: 5},
+		{Name: "SG", Eligible: []string{"SG"}, Count: 1, Priority: 5},
+		{Name: "SF", Eligible: []string{"SF"}, Count: 1, Priority: 5},
+		{Name: "PF", Eligible: []string{"PF"}, Count: 1, Priority: 5},
+		{Name: "C", Eligible: []string{"C"}, Count: 1, Priority: 3},
+		{Name: "UT", Eligible: []string{"PG", "SG", "SF", "PF", "C"}, Count: 2, Priority: 2},
+		{Name: "BE", Count: 4, Priority: 1, Kind: d.SlotBench},
+	}}
+}
+
+func TestCustomRosterTemplate(t *testing.T) {
+	d.InitSchedule("../static/schedule24-25.json")
+
+	template := smallRosterTemplate()
+	if err := template.Validate(); err != nil {
+		t.Fatalf("Template is invalid: %v", err)
+	}
+	if got := template.EligibleSlots([]string{"PG", "SG", "G", "UT1", "UT2", "UT3"}); !reflect.DeepEqual(got, []string{"PG", "SG", "UT1", "UT2"}) {
+		t.Errorf("Unexpected eligible slots %v", got)
+	}
+	if got := template.SlotsOfKind(d.SlotBench); len(got) != 4 {
+		t.Errorf("Expected 4 bench slots, got %v", got)
+	}
+
+	provider := d.FileProvider{RosterPath: l.MockRosterPath(), FreeAgentsPath: l.MockFreeAgentsPath()}
+	bt, err := team.InitBaseTeam(context.Background(), provider, &d.ScheduleMap, team.BaseTeamOptions{Template: template}, d.LeagueInfo{}, 50, "5", 0, 32.0)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Only the template's starting slots are used, every day
+	starters := map[string]bool{"PG": true, "SG": true, "SF": true, "PF": true, "C": true, "UT1": true, "UT2": true}
+	for day, lineup := range bt.OptimalSlotting {
+		if len(lineup) != len(starters) {
+			t.Errorf("Day %d has %d slots", day, len(lineup))
+		}
+		for pos := range lineup {
+			if !starters[pos] {
+				t.Errorf("Day %d uses slot %s", day, pos)
+			}
+		}
+		for pos := range bt.UnusedPositions[day] {
+			if !starters[pos] {
+				t.Errorf("Day %d has unused slot %s", day, pos)
+			}
+		}
+	}
+
+	// Streamers are slotted into the same layout
+	ev, err := p.InitPopulation(context.Background(), bt, u.DefaultOptimizerConfig(), rand.New(rand.NewSource(5)))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := ev.Evolve(context.Background(), bt); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, chromosome := range ev.Population {
+		for _, gene := range chromosome.Genes {
+			for pos := range gene.Roster {
+				if !starters[pos] {
+					t.Fatalf("Gene for day %d uses slot %s", gene.Day, pos)
+				}
+			}
+		}
+	}
+}
+
+func TestRosterTemplateInvalid(t *testing.T) {
+	invalid := []*d.RosterTemplate{
+		{},
+		{Slots: []d.RosterSlot{{Name: "BE", Count: 3, Kind: d.SlotBench}}},
+		{Slots: []d.RosterSlot{{Name: "PG", Eligible: []string{"PG"}, Count: 0}}},
+		{Slots: []d.RosterSlot{{Name: "UT", Count: 2}}},
+		{Slots: []d.RosterSlot{{Name: "PG", Eligible: []string{"PG"}, Count: 1}, {Name: "PG", Eligible: []string{"PG"}, Count: 1}}},
+		{Slots: []d.RosterSlot{{Name: "PG", Eligible: []string{"PG"}, Count: 1, Kind: "taxi"}}},
+	}
+	for _, template := range invalid {
+		if template.Validate() == nil {
+			t.Errorf("Expected %+v to be invalid", template)
+		}
+	}
+}
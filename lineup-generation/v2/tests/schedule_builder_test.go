@@ -0,0 +1,123 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	d "v2/data"
+	"v2/schedule"
+)
+
+// Function to build a feed of seven Monday-to-Sunday weeks starting 10/21/2024 with the given games
+func mockNBASchedule(games map[string][][2]string) *schedule.NBASchedule {
+
+	nba := &schedule.NBASchedule{}
+	starts := []string{"10-21", "10-28", "11-04", "11-11", "11-18", "11-25", "12-02"}
+	ends := []string{"10-27", "11-03", "11-10", "11-17", "11-24", "12-01", "12-08"}
+	for i := range starts {
+		nba.LeagueSchedule.Weeks = append(nba.LeagueSchedule.Weeks, schedule.NBAWeek{
+			WeekNumber: i + 1,
+			StartDate:  fmt.Sprintf("2024-%sT00:00:00Z", starts[i]),
+			EndDate:    fmt.Sprintf("2024-%sT00:00:00Z", ends[i]),
+		})
+	}
+	for _, date := range []string{"10/04/2024", "10/22/2024", "10/27/2024", "11/12/2024", "11/25/2024", "12/01/2024", "12/03/2024"} {
+		game_date := schedule.GameDate{GameDate: date + " 00:00:00"}
+		for _, matchup := range games[date] {
+			game_date.Games = append(game_date.Games, schedule.Game{HomeTeam: schedule.GameTeam{TeamTricode: matchup[0]}, AwayTeam: schedule.GameTeam{TeamTricode: matchup[1]}})
+		}
+		nba.LeagueSchedule.GameDates = append(nba.LeagueSchedule.GameDates, game_date)
+	}
+	return nba
+}
+
+func TestBuildSchedule(t *testing.T) {
+
+	nba := mockNBASchedule(map[string][][2]string{
+		"10/04/2024": {{"BOS", "NYK"}}, // Preseason
+		"10/22/2024": {{"BOS", "NYK"}},
+		"10/27/2024": {{"MIN", "BOS"}},
+		"11/12/2024": {{"MIN", "MEM"}}, // All-Star break, second week
+		"11/25/2024": {{"BOS", "MEM"}}, // Playoffs, first week
+		"12/01/2024": {{"NYK", "MIN"}},
+		"12/03/2024": {{"BOS", "NYK"}}, // After the season
+	})
+	calendar := &schedule.Calendar{SeasonStart: "10/22/2024", AllStarWeeks: []int{3, 4}, PlayoffMatchups: [][]int{{5, 6}}}
+
+	season, err := schedule.Build(nba, calendar)
+	if err != nil {
+		t.Fatalf("Failed to build schedule: %v", err)
+	}
+
+	// Weeks 1 and 2, the combined All-Star weeks 3 and 4, and the two week playoff matchup
+	expected := map[string]d.WeekSchedule{
+		"1": {StartDate: "10/22/2024", EndDate: "10/27/2024", GameSpan: 5},
+		"2": {StartDate: "10/28/2024", EndDate: "11/03/2024", GameSpan: 6},
+		"3": {StartDate: "11/04/2024", EndDate: "11/17/2024", GameSpan: 13},
+		"4": {StartDate: "11/18/2024", EndDate: "12/01/2024", GameSpan: 13},
+	}
+	if len(season.Schedule) != len(expected) {
+		t.Fatalf("Expected %d matchups, got %d", len(expected), len(season.Schedule))
+	}
+	for week, want := range expected {
+		got := season.Schedule[week]
+		if got.StartDate != want.StartDate || got.EndDate != want.EndDate || got.GameSpan != want.GameSpan {
+			t.Errorf("Week %s is %s-%s (%d), expected %s-%s (%d)", week, got.StartDate, got.EndDate, got.GameSpan, want.StartDate, want.EndDate, want.GameSpan)
+		}
+	}
+
+	// Games are keyed by the day of the matchup
+	checks := []struct {
+		week    string
+		day     int
+		team    string
+		playing bool
+	}{
+		{"1", 0, "BOS", true},
+		{"1", 0, "NYK", true},
+		{"1", 5, "MIN", true},
+		{"1", 0, "MIN", false},
+		{"3", 8, "MEM", true},
+		{"4", 7, "BOS", true},
+		{"4", 13, "NYK", true},
+	}
+	for _, c := range checks {
+		if season.IsPlaying(c.week, c.day, c.team) != c.playing {
+			t.Errorf("Expected %s playing on day %d of week %s to be %v", c.team, c.day, c.week, c.playing)
+		}
+	}
+	if len(season.Schedule["1"].TeamSchedules["BOS"]) != 2 {
+		t.Errorf("Preseason game was included")
+	}
+
+	// The output has to decode into the format the optimizer reads
+	path := filepath.Join(t.TempDir(), "schedule.json")
+	if err := schedule.Write(path, season); err != nil {
+		t.Fatalf("Failed to write schedule: %v", err)
+	}
+	bytes, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read schedule: %v", err)
+	}
+	var loaded d.SeasonSchedule
+	if err := json.Unmarshal(bytes, &loaded); err != nil || !loaded.IsPlaying("4", 7, "BOS") {
+		t.Errorf("Written schedule did not decode: %v", err)
+	}
+}
+
+func TestCalendarMatchupsInvalid(t *testing.T) {
+
+	weeks := []int{1, 2, 3, 4, 5, 6, 7}
+	invalid := []*schedule.Calendar{
+		{AllStarWeeks: []int{3, 5}},
+		{PlayoffMatchups: [][]int{{5, 6}, {8}}},
+		{PlayoffMatchups: [][]int{{}}},
+	}
+	for _, calendar := range invalid {
+		if _, err := calendar.Matchups(weeks); err == nil {
+			t.Errorf("Expected error for calendar %+v", calendar)
+		}
+	}
+}
@@ -1,8 +1,10 @@
 package tests
 
 import (
+	"errors"
 	"strconv"
 	"testing"
+	"time"
 	d "v2/data"
 )
 
@@ -19,4 +21,69 @@ func TestSchedule(t *testing.T) {
 		}
 	}
 
-}
\ No newline at end of file
+}
+func TestScheduleDates(t *testing.T) {
+
+	schedule, err := d.ReadSchedule("../static/schedule24-25.json")
+	if err != nil {
+		t.Fatalf("Failed to read schedule: %v", err)
+	}
+
+	// Dates map to the week they fall in and how far into it they are
+	cases := []struct {
+		date string
+		week string
+		day  int
+	}{
+		{"2024-10-22", "1", 0},
+		{"2024-10-27", "1", 5},
+		{"2024-11-20", "5", 2},
+		{"2025-03-30", "21", 13},
+	}
+	for _, c := range cases {
+		date, _ := time.Parse(d.RequestDateLayout, c.date)
+		week, day, err := schedule.WeekForDate(date)
+		if err != nil || week != c.week || day != c.day {
+			t.Errorf("%s mapped to week %s day %d (%v), expected week %s day %d", c.date, week, day, err, c.week, c.day)
+		}
+	}
+	if _, _, err := schedule.WeekForDate(time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)); !errors.Is(err, d.ErrDateNotInSeason) {
+		t.Errorf("Expected ErrDateNotInSeason, got %v", err)
+	}
+
+	// Every date in a week lines up with its day offset
+	dates, err := schedule.WeekDates("1")
+	if err != nil || len(dates) != 6 || dates[5].Format(d.DateLayout) != "10/27/2024" {
+		t.Errorf("Unexpected dates for week 1: %v (%v)", dates, err)
+	}
+
+	// Opening night was NYK at BOS and MIN at LAL
+	opening_night := time.Date(2024, 10, 22, 20, 0, 0, 0, time.UTC)
+	if !schedule.TeamPlaysOn("BOS", opening_night) || schedule.TeamPlaysOn("MEM", opening_night) {
+		t.Errorf("Unexpected games on opening night")
+	}
+
+	// An explicit week wins over a date, which wins over today. Dates also say how far into the week the request is
+	now := time.Date(2024, 11, 20, 12, 0, 0, 0, time.UTC)
+	resolve := []struct {
+		week string
+		date string
+		want string
+		day  int
+	}{
+		{"3", "2024-11-20", "3", 0},
+		{"", "2024-10-30", "2", 2},
+		{"", "", "5", 2},
+	}
+	for _, c := range resolve {
+		if week, day, err := schedule.ResolveWeek(c.week, c.date, now); err != nil || week != c.want || day != c.day {
+			t.Errorf("Resolved week %q date %q to week %s day %d (%v), expected week %s day %d", c.week, c.date, week, day, err, c.want, c.day)
+		}
+	}
+	if _, _, err := schedule.ResolveWeek("", "11/20/2024", now); !errors.Is(err, d.ErrInvalidDate) {
+		t.Errorf("Expected ErrInvalidDate, got %v", err)
+	}
+	if _, _, err := schedule.ResolveWeek("40", "", now); !errors.Is(err, d.ErrWeekNotFound) {
+		t.Errorf("Expected ErrWeekNotFound, got %v", err)
+	}
+}
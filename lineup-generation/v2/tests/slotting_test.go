@@ -0,0 +1,187 @@
+package tests
+
+import (
+	"math/rand"
+	"strconv"
+	"testing"
+	d "v2/data"
+	p "v2/population"
+	"v2/team"
+)
+
+// Function to slot players the old way, by trying every assignment
+func fitPlayers(bt *team.BaseTeam, players []d.Player) map[string]d.Player {
+	ctx := &team.FitPlayersContext{BestLineup: make(map[string]d.Player), MaxScore: bt.CalculateMaxScore(players)}
+	bt.FitPlayers(players, make(map[string]d.Player), bt.GetRosterTemplate().SlotOrder(), ctx, 0)
+	return ctx.BestLineup
+}
+
+// Function to make a day's worth of players with random positions, all playing for the same team
+func randomPlayers(rng *rand.Rand, template *d.RosterTemplate, count int) []d.Player {
+	positions := []string{"PG", "SG", "SF", "PF", "C"}
+	players := make([]d.Player, count)
+	for i := range players {
+		var valid []string
+		for _, pos := range positions {
+			if rng.Intn(3) == 0 {
+				valid = append(valid, pos)
+			}
+		}
+		if len(valid) == 0 {
+			valid = append(valid, positions[rng.Intn(len(positions))])
+		}
+		players[i] = template.FitPlayer(d.Player{Name: "Player " + strconv.Itoa(i), AvgPoints: 15 + 30*rng.Float64(), Team: "BOS", ValidPositions: valid})
+	}
+	return players
+}
+
+func TestMatchPlayers(t *testing.T) {
+	bt := loadMockTeam("5", 32.0)
+	template := bt.GetRosterTemplate()
+
+	// The matching is always as restrictive as the search, and never starts a player somewhere he can't play
+	rng := rand.New(rand.NewSource(5))
+	for trial := 0; trial < 200; trial++ {
+		players := randomPlayers(rng, template, 1+rng.Intn(9))
+		if trial == 0 {
+			players = heavyDay(bt, 8)
+		}
+		matched := bt.MatchPlayers(players, template.SlotOrder(), bt.StartDay)
+		if got, want := bt.ScoreRoster(matched), bt.ScoreRoster(fitPlayers(bt, players)); got < want {
+			t.Fatalf("Trial %d: matching scored %d, search scored %d", trial, got, want)
+		}
+		for pos, player := range matched {
+			if !player.PlaysPosition(pos) {
+				t.Fatalf("Trial %d: %s can't play %s", trial, player.Name, pos)
+			}
+		}
+	}
+
+	// Between lineups that are just as restrictive, the better player starts
+	guards := []d.Player{
+		template.FitPlayer(d.Player{Name: "Worse", AvgPoints: 10, ValidPositions: []string{"PG"}}),
+		template.FitPlayer(d.Player{Name: "Better", AvgPoints: 40, ValidPositions: []string{"PG"}}),
+	}
+	if lineup := bt.MatchPlayers(guards, []string{"PG"}, bt.StartDay); lineup["PG"].Name != "Better" {
+		t.Errorf("Expected the better player to start, got %s", lineup["PG"].Name)
+	}
+}
+
+func TestGeneReslot(t *testing.T) {
+	bt := loadMockTeam("5", 32.0)
+	template := bt.GetRosterTemplate()
+
+	// Only PG and C are open, and the PG slot is taken by someone who could move to C
+	day := bt.StartDay
+	bt.UnusedPositions = map[int]map[string]bool{day: {"PG": true, "C": true}}
+	var flexible, point_guard d.Player
+	for _, player := range bt.FreeAgents {
+		if bt.CanPlay(player, day) && flexible.Name == "" {
+			flexible = template.FitPlayer(d.Player{Name: "Flexible", AvgPoints: 20, Team: player.Team, ValidPositions: []string{"PG", "C"}})
+			point_guard = template.FitPlayer(d.Player{Name: "Point Guard", AvgPoints: 20, Team: player.Team, ValidPositions: []string{"PG"}})
+		}
+	}
+	gene := p.InitGene(bt, day)
+	gene.SetLineup(bt, map[string]d.Player{"PG": flexible}, []d.Player{flexible})
+
+	gene.SlotPlayer(bt, point_guard)
+	if gene.Roster["PG"].Name != "Point Guard" || gene.Roster["C"].Name != "Flexible" {
+		t.Errorf("Expected the flexible player to move to C, got %v", gene.Roster)
+	}
+	if gene.Bench.IsOnBench(point_guard) || gene.FreePositions["PG"] || gene.FreePositions["C"] {
+		t.Errorf("Expected both open positions to be filled")
+	}
+}
+
+// Function to get a heavy game day where the lineup can't fill every slot: frontcourt players only, so the search can't stop early
+func heavyDay(bt *team.BaseTeam, count int) []d.Player {
+	rng := rand.New(rand.NewSource(1))
+	positions := [][]string{{"SF"}, {"PF"}, {"C"}, {"SF", "PF"}, {"PF", "C"}}
+	players := make([]d.Player, count)
+	for i := range players {
+		valid := positions[rng.Intn(len(positions))]
+		players[i] = bt.GetRosterTemplate().FitPlayer(d.Player{Name: "Player " + strconv.Itoa(i), AvgPoints: 15 + 30*rng.Float64(), Team: "BOS", ValidPositions: valid})
+	}
+	return players
+}
+
+func BenchmarkFitPlayers(b *testing.B) {
+	bt := loadMockTeam("5", 32.0)
+	for _, count := range []int{8, 10, 12} {
+		players := heavyDay(bt, count)
+		b.Run(strconv.Itoa(count), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				fitPlayers(bt, players)
+			}
+		})
+	}
+}
+
+func BenchmarkMatchPlayers(b *testing.B) {
+	bt := loadMockTeam("5", 32.0)
+	slots := bt.GetRosterTemplate().SlotOrder()
+	for _, count := range []int{8, 10, 12} {
+		players := heavyDay(bt, count)
+		b.Run(strconv.Itoa(count), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				bt.MatchPlayers(players, slots, bt.StartDay)
+			}
+		})
+	}
+}
+
+func TestPointsSlotting(t *testing.T) {
+	bt := loadMockTeam("5", 32.0)
+	bt.Slotting = d.SlottingPoints
+	template := bt.GetRosterTemplate()
+
+	// Slotting for points always starts at least as many points as the search for restrictiveness
+	started := func(lineup map[string]d.Player) float64 {
+		total := 0.0
+		for _, player := range lineup {
+			total += player.AvgPoints
+		}
+		return total
+	}
+	rng := rand.New(rand.NewSource(9))
+	for trial := 0; trial < 200; trial++ {
+		players := randomPlayers(rng, template, 1+rng.Intn(9))
+		if got, want := started(bt.MatchPlayers(players, template.SlotOrder(), bt.StartDay)), started(fitPlayers(bt, players)); got < want-1e-9 {
+			t.Fatalf("Trial %d: slotting for points started %v, the search started %v", trial, got, want)
+		}
+	}
+
+	// Restrictiveness breaks ties
+	guard := template.FitPlayer(d.Player{Name: "Guard", AvgPoints: 20, ValidPositions: []string{"PG"}})
+	if lineup := bt.MatchPlayers([]d.Player{guard}, []string{"UT1", "PG"}, bt.StartDay); lineup["PG"].Name != "Guard" {
+		t.Errorf("Expected the guard to take the more restrictive slot, got %v", lineup)
+	}
+
+	// A better streamer takes the open position from a worse one, who goes to the bench
+	day := bt.StartDay
+	bt.UnusedPositions = map[int]map[string]bool{day: {"PG": true}}
+	var worse, better d.Player
+	for _, player := range bt.FreeAgents {
+		if bt.CanPlay(player, day) && worse.Name == "" {
+			worse = template.FitPlayer(d.Player{Name: "Worse", AvgPoints: 10, Team: player.Team, ValidPositions: []string{"PG"}})
+			better = template.FitPlayer(d.Player{Name: "Better", AvgPoints: 40, Team: player.Team, ValidPositions: []string{"PG"}})
+		}
+	}
+	gene := p.InitGene(bt, day)
+	gene.FreePositions["PG"] = true
+	gene.SlotPlayer(bt, worse)
+	gene.SlotPlayer(bt, better)
+	if gene.Roster["PG"].Name != "Better" || !gene.Bench.IsOnBench(worse) || gene.Bench.IsOnBench(better) {
+		t.Errorf("Expected the better streamer to start over the worse one, got %v", gene.Roster)
+	}
+
+	// Filling the most restrictive slots leaves the first streamer where he is
+	bt.Slotting = d.SlottingRestrictive
+	gene = p.InitGene(bt, day)
+	gene.FreePositions["PG"] = true
+	gene.SlotPlayer(bt, worse)
+	gene.SlotPlayer(bt, better)
+	if gene.Roster["PG"].Name != "Worse" || !gene.Bench.IsOnBench(better) {
+		t.Errorf("Expected the first streamer to keep the position, got %v", gene.Roster)
+	}
+}
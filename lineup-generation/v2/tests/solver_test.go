@@ -0,0 +1,162 @@
+package tests
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+	d "v2/data"
+	p "v2/population"
+	"v2/team"
+	u "v2/utils"
+)
+
+func TestSolverOracle(t *testing.T) {
+
+	// Small scenarios that the exact solver can search to the end
+	cases := []struct {
+		name  string
+		limit d.AcquisitionLimit
+	}{
+		{"no moves", d.AcquisitionLimit{Period: d.PeriodMatchup, Limit: 0, Hard: true}},
+		{"one move", d.AcquisitionLimit{Period: d.PeriodMatchup, Limit: 1, Hard: true}},
+		{"two moves", d.AcquisitionLimit{Period: d.PeriodMatchup, Limit: 2, Hard: true}},
+		{"daily cap", d.AcquisitionLimit{Period: d.PeriodMatchup, Limit: 3, DailyCap: 1, Hard: true}},
+	}
+	for _, c := range cases {
+		bt := loadMockTeam("5", 32.0)
+		bt.FreeAgents = bt.FreeAgents[:8]
+		bt.AcquisitionLimit = c.limit
+
+		result := p.Solve(context.Background(), bt, p.SolverOptions{})
+		if !result.Exact || result.Gap() != 0 {
+			t.Fatalf("%s: expected the search to finish, gap %v", c.name, result.Gap())
+		}
+		if best := result.Chromosome; !best.IsFeasible() || math.Abs(best.Points()-result.Value) > 1e-6 {
+			t.Fatalf("%s: solver's lineup scores %v, expected %v", c.name, best.Points(), result.Value)
+		}
+
+		// The genetic algorithm can never beat the optimum, and the gap says how far off it is
+		config := u.DefaultOptimizerConfig()
+		config.Fitness = u.FitnessPoints
+		archipelago, _ := p.InitArchipelago(context.Background(), bt, config, rand.New(rand.NewSource(3)))
+		if err := archipelago.Evolve(context.Background(), bt, config.IslandGenerations); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		best := archipelago.Merge().BestValid()
+		if best == nil {
+			t.Fatalf("%s: genetic algorithm found no valid lineup", c.name)
+		}
+		if best.Points() > result.Value+1e-6 {
+			t.Errorf("%s: genetic algorithm scored %v, above the optimum of %v", c.name, best.Points(), result.Value)
+		}
+		t.Logf("%s: optimum %.2f, genetic algorithm %.2f, gap %.2f%%", c.name, result.Value, best.Points(), 100*(result.Value-best.Points())/result.Value)
+	}
+}
+
+func TestSolverReAdd(t *testing.T) {
+	d.InitSchedule("../static/schedule24-25.json")
+
+	// A streamer who only plays at either end of the week and a free agent who plays in the middle of it. The best plan swaps the
+	// streamer out for the middle of the week and picks him back up once he is off waivers
+	streamer := d.Player{Name: "Streamer", AvgPoints: 30, Team: "MIA", ValidPositions: []string{"C"}}
+	free_agent := d.Player{Name: "Free Agent", AvgPoints: 20, Team: "OKC", ValidPositions: []string{"C"}}
+	options := team.BaseTeamOptions{AcquisitionLimit: d.AcquisitionLimit{Period: d.PeriodMatchup, Limit: 2, Hard: true}}
+	bt := team.BuildBaseTeam(&d.ScheduleMap, options, map[string]d.Player{streamer.Name: streamer}, []d.Player{free_agent}, "5", 0, 32.0)
+
+	result := p.Solve(context.Background(), bt, p.SolverOptions{})
+	if !result.Exact || math.Abs(result.Value-100) > 1e-6 {
+		t.Fatalf("Expected the plan with the streamer picked back up to score 100, got %v", result.Value)
+	}
+	re_added := false
+	for _, gene := range result.Chromosome.Genes {
+		for _, player := range gene.NewPlayers {
+			re_added = re_added || player.Name == streamer.Name && gene.Day >= 1+p.WaiverDays
+		}
+	}
+	if !re_added {
+		t.Errorf("Expected the streamer to be picked back up once off waivers")
+	}
+
+	// The genetic algorithm, over the same 7-day week, never beats the optimum
+	config := u.DefaultOptimizerConfig()
+	config.Fitness = u.FitnessPoints
+	archipelago, _ := p.InitArchipelago(context.Background(), bt, config, rand.New(rand.NewSource(3)))
+	if err := archipelago.Evolve(context.Background(), bt, config.IslandGenerations); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if best := archipelago.Merge().BestValid(); best != nil && best.Points() > result.Value+1e-6 {
+		t.Errorf("Genetic algorithm scored %v, above the optimum of %v", best.Points(), result.Value)
+	}
+}
+
+func TestSolverNodeLimit(t *testing.T) {
+	bt := loadMockTeam("5", 32.0)
+	bt.FreeAgents = bt.FreeAgents[:8]
+	bt.AcquisitionLimit = d.AcquisitionLimit{Period: d.PeriodMatchup, Limit: 2, Hard: true}
+
+	exact := p.Solve(context.Background(), bt, p.SolverOptions{})
+	limited := p.Solve(context.Background(), bt, p.SolverOptions{MaxNodes: 10})
+
+	// A search cut short still returns a plan, and its bound covers the optimum
+	if limited.Exact || limited.Chromosome == nil {
+		t.Fatalf("Expected a plan from a search cut short after %d nodes", limited.Nodes)
+	}
+	if limited.Value > exact.Value+1e-6 || limited.Bound < exact.Value-1e-6 {
+		t.Errorf("Expected %v <= %v <= %v", limited.Value, exact.Value, limited.Bound)
+	}
+
+	// A cancelled search over the whole free agent pool stops straight away with the plan of making no moves
+	full := loadMockTeam("5", 32.0)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	start := time.Now()
+	cancelled := p.Solve(ctx, full, p.SolverOptions{})
+	if cancelled.Exact || cancelled.Chromosome == nil || cancelled.Chromosome.TotalAcquisitions != 0 {
+		t.Errorf("Expected the plan of making no moves from a cancelled search")
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Cancelled search took %v", elapsed)
+	}
+}
+
+func TestSolverNodeLimitFullPool(t *testing.T) {
+
+	// With a high threshold there are plenty of streamers to drop and the whole free agent pool to pick up from, and a small budget
+	// still returns straight away
+	bt := loadMockTeam("5", 45.0)
+	start := time.Now()
+	result := p.Solve(context.Background(), bt, p.SolverOptions{MaxNodes: 10})
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Search with a budget of 10 nodes took %v", elapsed)
+	}
+	if !result.Stopped || result.Exact || result.Chromosome == nil || result.Nodes > 11 {
+		t.Errorf("Expected a plan from a search stopped after 10 nodes, searched %d", result.Nodes)
+	}
+	if result.Bound < result.Value {
+		t.Errorf("Bound %v is below the plan found %v", result.Bound, result.Value)
+	}
+}
+
+func TestBestLineup(t *testing.T) {
+	bt := loadMockTeam("5", 32.0)
+
+	// The best lineup is at least as good as slotting the streamers greedily
+	for i, gene := range p.InitChromosome(bt).Genes {
+		gene.InsertStreamablePlayers(bt)
+		greedy := 0.0
+		for _, player := range gene.Roster {
+			greedy += gene.ExpectedPoints(player)
+		}
+		value, lineup := p.BestLineup(bt, gene.Day, bt.StreamablePlayers)
+		if value < greedy-1e-6 {
+			t.Errorf("Gene %d: best lineup scores %v, below the greedy %v", i, value, greedy)
+		}
+		for pos, player := range lineup {
+			if !bt.UnusedPositions[gene.Day][pos] || !u.Contains(player.ValidPositions, pos) {
+				t.Errorf("Gene %d: %s can't play %s", i, player.Name, pos)
+			}
+		}
+	}
+}
@@ -3,6 +3,8 @@ package tests
 import (
 	"fmt"
 	"runtime"
+	d "v2/data"
+	"v2/team"
 )
 
 func printMemUsage() {
@@ -16,4 +18,10 @@ func printMemUsage() {
 
 func bToMb(b uint64) uint64 {
 	return b / 1024 / 1024
-}
\ No newline at end of file
+}
+
+// Function to build the mock BaseTeam against the schedule checked into the repo
+func loadMockTeam(week string, threshold float64) *team.BaseTeam {
+	d.InitSchedule("../static/schedule24-25.json")
+	return team.InitBaseTeamMock(week, threshold)
+}
@@ -0,0 +1,226 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrInvalidOptimizer = errors.New("invalid optimizer settings")
+
+// Optional tuning knobs for the genetic algorithm as they come in on the request. Every field is a pointer so that an omitted field can be told apart from an explicit zero
+type OptimizerOptions struct {
+	PopulationSize      *int     `json:"population_size"`
+	NumIslands          *int     `json:"num_islands"`
+	IslandGenerations   *int     `json:"island_generations"`
+	CombinedGenerations *int     `json:"combined_generations"`
+	MutationRate        *float64 `json:"mutation_rate"`
+	FaCount             *int     `json:"fa_count"`
+	TournamentCount     *int     `json:"tournament_count"`
+	TournamentSize      *int     `json:"tournament_size"`
+	TournamentPick      *int     `json:"tournament_pick"`
+	RouletteExponent    *float64 `json:"roulette_exponent"`
+	RouletteFloor       *float64 `json:"roulette_floor"`
+	MigrationTopology   *string  `json:"migration_topology"`
+	MigrationInterval   *int     `json:"migration_interval"`
+	NumEmigrants        *int     `json:"num_emigrants"`
+	TimeBudgetMs        *int     `json:"time_budget_ms"`
+	Mode                *string  `json:"mode"`
+	SolverNodes         *int     `json:"solver_nodes"`
+	Fitness             *string  `json:"fitness"`
+	MoveCost            *float64 `json:"move_cost"`
+	RiskAversion        *float64 `json:"risk_aversion"`
+}
+
+// Resolved tuning knobs that the optimizer actually runs with
+type OptimizerConfig struct {
+	PopulationSize      int     `json:"population_size"`
+	NumIslands          int     `json:"num_islands"`
+	IslandGenerations   int     `json:"island_generations"`
+	CombinedGenerations int     `json:"combined_generations"`
+	MutationRate        float64 `json:"mutation_rate"`
+	FaCount             int     `json:"fa_count"`
+	TournamentCount     int     `json:"tournament_count"`
+	TournamentSize      int     `json:"tournament_size"`
+	TournamentPick      int     `json:"tournament_pick"`
+	RouletteExponent    float64 `json:"roulette_exponent"`
+	RouletteFloor       float64 `json:"roulette_floor"`
+	MigrationTopology   string  `json:"migration_topology"`
+	MigrationInterval   int     `json:"migration_interval"`
+	NumEmigrants        int     `json:"num_emigrants"`
+	TimeBudgetMs        int     `json:"time_budget_ms"` // 0 means the run is only bounded by its generation counts
+	Mode                string  `json:"mode"`
+	SolverNodes         int     `json:"solver_nodes"` // Nodes the exact solver searches before settling for the best plan so far, 0 for no limit
+	Fitness             string  `json:"fitness"`
+	MoveCost            float64 `json:"move_cost"`     // Points each acquisition costs with the move_cost fitness
+	RiskAversion        float64 `json:"risk_aversion"` // Points given up per unit of variance with the mean_variance fitness
+}
+
+// Supported ways of connecting islands for migration
+const (
+	TopologyRing = "ring" // Each island sends emigrants to the next island
+	TopologyFull = "full" // Each island sends emigrants to every other island
+)
+
+// Supported ways of searching for the best lineup
+const (
+	ModeGenetic = "genetic" // The genetic algorithm
+	ModeExact   = "exact"   // Branch and bound, which is exact for small free agent pools
+)
+
+// Supported fitness functions for scoring chromosomes
+const (
+	FitnessTruncated      = "truncated"       // Expected points rounded down to a whole number, which the optimizer has always used
+	FitnessPoints         = "points"          // Expected points without rounding
+	FitnessMoveCost       = "move_cost"       // Expected points less a cost for every acquisition
+	FitnessMeanVariance   = "mean_variance"   // Expected points less a penalty for how much they could swing
+	FitnessWinProbability = "win_probability" // Chance of winning the matchup, which needs the opponent
+)
+
+// Server-side caps so that a single request can't monopolize the instance
+const (
+	MaxPopulationSize = 100
+	MaxNumIslands     = 8
+	MaxGenerations    = 100
+	MaxFaCount        = 250
+	MaxTournaments    = 10
+	MaxRouletteExp    = 5.0
+	MaxTimeBudgetMs   = 120000
+	MaxMoveCost       = 100.0
+	MaxRiskAversion   = 1.0
+	MaxSolverNodes    = 20000000
+)
+
+// Function to get the settings the optimizer has always run with
+func DefaultOptimizerConfig() OptimizerConfig {
+	return OptimizerConfig{
+		PopulationSize:      20,
+		NumIslands:          2,
+		IslandGenerations:   10,
+		CombinedGenerations: 10,
+		MutationRate:        0.20,
+		FaCount:             100,
+		TournamentCount:     3,
+		TournamentSize:      5,
+		TournamentPick:      1,
+		RouletteExponent:    1.5,
+		RouletteFloor:       0.02,
+		MigrationTopology:   TopologyRing,
+		MigrationInterval:   5,
+		NumEmigrants:        2,
+		Mode:                ModeGenetic,
+		SolverNodes:         2000000,
+		Fitness:             FitnessTruncated,
+		MoveCost:            5.0,
+		RiskAversion:        0.01,
+	}
+}
+
+// Function to overlay the request options on top of the defaults, rejecting nonsensical values and clamping anything above the server-side caps
+func (o *OptimizerOptions) Resolve() (OptimizerConfig, error) {
+	config := DefaultOptimizerConfig()
+	if o == nil {
+		return config, nil
+	}
+
+	// Helper to validate an integer option against a minimum and clamp it to a cap
+	resolve_int := func(name string, value *int, min int, max int, dst *int) error {
+		if value == nil {
+			return nil
+		}
+		if *value < min {
+			return fmt.Errorf("%w: optimizer.%s must be at least %d", ErrInvalidOptimizer, name, min)
+		}
+		*dst = *value
+		if *dst > max {
+			*dst = max
+		}
+		return nil
+	}
+
+	// Helper to validate a float option against a minimum and clamp it to a cap
+	resolve_float := func(name string, value *float64, min float64, max float64, dst *float64) error {
+		if value == nil {
+			return nil
+		}
+		if *value < min {
+			return fmt.Errorf("%w: optimizer.%s must be at least %v", ErrInvalidOptimizer, name, min)
+		}
+		*dst = *value
+		if *dst > max {
+			*dst = max
+		}
+		return nil
+	}
+
+	checks := []error{
+		resolve_int("population_size", o.PopulationSize, 2, MaxPopulationSize, &config.PopulationSize),
+		resolve_int("num_islands", o.NumIslands, 1, MaxNumIslands, &config.NumIslands),
+		resolve_int("island_generations", o.IslandGenerations, 0, MaxGenerations, &config.IslandGenerations),
+		resolve_int("combined_generations", o.CombinedGenerations, 0, MaxGenerations, &config.CombinedGenerations),
+		resolve_float("mutation_rate", o.MutationRate, 0.0, 1.0, &config.MutationRate),
+		resolve_int("fa_count", o.FaCount, 1, MaxFaCount, &config.FaCount),
+		resolve_int("tournament_count", o.TournamentCount, 1, MaxTournaments, &config.TournamentCount),
+		resolve_int("tournament_size", o.TournamentSize, 1, MaxPopulationSize, &config.TournamentSize),
+		resolve_int("tournament_pick", o.TournamentPick, 0, MaxPopulationSize, &config.TournamentPick),
+		resolve_float("roulette_exponent", o.RouletteExponent, 0.0, MaxRouletteExp, &config.RouletteExponent),
+		resolve_float("roulette_floor", o.RouletteFloor, 0.0, 1.0, &config.RouletteFloor),
+		resolve_int("migration_interval", o.MigrationInterval, 1, MaxGenerations, &config.MigrationInterval),
+		resolve_int("num_emigrants", o.NumEmigrants, 0, MaxPopulationSize, &config.NumEmigrants),
+		resolve_int("time_budget_ms", o.TimeBudgetMs, 0, MaxTimeBudgetMs, &config.TimeBudgetMs),
+		resolve_int("solver_nodes", o.SolverNodes, 0, MaxSolverNodes, &config.SolverNodes),
+		resolve_float("move_cost", o.MoveCost, 0.0, MaxMoveCost, &config.MoveCost),
+		resolve_float("risk_aversion", o.RiskAversion, 0.0, MaxRiskAversion, &config.RiskAversion),
+	}
+	for _, err := range checks {
+		if err != nil {
+			return OptimizerConfig{}, err
+		}
+	}
+
+	// The topology has to be one that the archipelago knows how to wire up
+	if o.MigrationTopology != nil {
+		switch *o.MigrationTopology {
+		case TopologyRing, TopologyFull:
+			config.MigrationTopology = *o.MigrationTopology
+		default:
+			return OptimizerConfig{}, fmt.Errorf("%w: optimizer.migration_topology must be %q or %q", ErrInvalidOptimizer, TopologyRing, TopologyFull)
+		}
+	}
+
+	// The mode has to be one the server knows how to run
+	if o.Mode != nil {
+		switch *o.Mode {
+		case ModeGenetic, ModeExact:
+			config.Mode = *o.Mode
+		default:
+			return OptimizerConfig{}, fmt.Errorf("%w: optimizer.mode must be %q or %q", ErrInvalidOptimizer, ModeGenetic, ModeExact)
+		}
+	}
+
+	// The fitness function has to be one that the population knows how to score with
+	if o.Fitness != nil {
+		switch *o.Fitness {
+		case FitnessTruncated, FitnessPoints, FitnessMoveCost, FitnessMeanVariance, FitnessWinProbability:
+			config.Fitness = *o.Fitness
+		default:
+			return OptimizerConfig{}, fmt.Errorf("%w: optimizer.fitness must be one of %q, %q, %q, %q or %q", ErrInvalidOptimizer, FitnessTruncated, FitnessPoints, FitnessMoveCost, FitnessMeanVariance, FitnessWinProbability)
+		}
+	}
+
+	// The exact solver only knows how to maximize expected points
+	if config.Mode == ModeExact && config.Fitness != FitnessTruncated && config.Fitness != FitnessPoints {
+		return OptimizerConfig{}, fmt.Errorf("%w: optimizer.mode %q only supports optimizer.fitness %q or %q", ErrInvalidOptimizer, ModeExact, FitnessTruncated, FitnessPoints)
+	}
+
+	// Emigrants are copies of an island's best chromosomes, so there can't be more of them than the island holds
+	if config.NumEmigrants >= config.PopulationSize {
+		config.NumEmigrants = config.PopulationSize - 1
+	}
+
+	// The tournament pick is an index into a sorted tournament, so it has to fit inside one
+	if config.TournamentPick >= config.TournamentSize {
+		return OptimizerConfig{}, fmt.Errorf("%w: optimizer.tournament_pick must be less than optimizer.tournament_size (%d)", ErrInvalidOptimizer, config.TournamentSize)
+	}
+
+	return config, nil
+}
@@ -0,0 +1,87 @@
+package utils
+
+import "math"
+
+// Function to assign rows to columns so that the total weight is as large as possible, using the Hungarian algorithm. Each row gets
+// at most one column and each column at most one row, and a row is left out rather than given a weight of 0 or less. Returns the
+// column assigned to each row, or -1 for rows that were left out
+func MaxWeightMatching(weights [][]float64) []int {
+
+	rows := len(weights)
+	cols := 0
+	for _, row := range weights {
+		cols = max(cols, len(row))
+	}
+
+	// Pad to a square cost matrix, where leaving a row out or assigning it somewhere it can't go costs nothing
+	size := max(rows, cols)
+	cost := func(i int, j int) float64 {
+		if i < rows && j < len(weights[i]) && weights[i][j] > 0 {
+			return -weights[i][j]
+		}
+		return 0
+	}
+
+	// Potentials for rows and columns, the row matched to each column and the column each column was reached from, all 1-indexed
+	// so that column 0 can stand in for the row being added
+	row_potential := make([]float64, size+1)
+	col_potential := make([]float64, size+1)
+	matched := make([]int, size+1)
+	way := make([]int, size+1)
+
+	for i := 1; i <= size; i++ {
+		matched[0] = i
+		col := 0
+		min_slack := make([]float64, size+1)
+		used := make([]bool, size+1)
+		for j := range min_slack {
+			min_slack[j] = math.Inf(1)
+		}
+
+		// Grow an alternating tree from row i until it reaches a free column
+		for matched[col] != 0 {
+			used[col] = true
+			row := matched[col]
+			delta, next := math.Inf(1), 0
+			for j := 1; j <= size; j++ {
+				if used[j] {
+					continue
+				}
+				slack := cost(row-1, j-1) - row_potential[row] - col_potential[j]
+				if slack < min_slack[j] {
+					min_slack[j], way[j] = slack, col
+				}
+				if min_slack[j] < delta {
+					delta, next = min_slack[j], j
+				}
+			}
+			for j := 0; j <= size; j++ {
+				if used[j] {
+					row_potential[matched[j]] += delta
+					col_potential[j] -= delta
+				} else {
+					min_slack[j] -= delta
+				}
+			}
+			col = next
+		}
+
+		// Flip the path back to row i
+		for col != 0 {
+			prev := way[col]
+			matched[col] = matched[prev]
+			col = prev
+		}
+	}
+
+	assignment := make([]int, rows)
+	for i := range assignment {
+		assignment[i] = -1
+	}
+	for j := 1; j <= size; j++ {
+		if row := matched[j] - 1; row < rows && j-1 < len(weights[row]) && weights[row][j-1] > 0 {
+			assignment[row] = j - 1
+		}
+	}
+	return assignment
+}
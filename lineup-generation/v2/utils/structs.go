@@ -8,18 +8,27 @@ import (
 
 // Struct to simplify keeping bench in sorted order (ascending points)
 type Bench struct {
-	Players []d.Player
+	Players     []d.Player
+	Projections map[string]float64
 }
 
 
 func (b *Bench) AddPlayer(p d.Player) {
 	b.Players = append(b.Players, p)
 	sort.Slice(b.Players, func(i, j int) bool {
-		return b.Players[i].AvgPoints < b.Players[j].AvgPoints
+		return b.Points(b.Players[i]) < b.Points(b.Players[j])
 	})
 
 }
 
+// Function to get the points a benched player is projected to score that day, falling back to his season average
+func (b *Bench) Points(p d.Player) float64 {
+	if points, ok := b.Projections[p.Name]; ok {
+		return points
+	}
+	return p.AvgPoints
+}
+
 func (b *Bench) RemovePlayer(p d.Player) (d.Player, bool) {
 	for i, player := range b.Players {
 		if player.Name == p.Name {
@@ -72,6 +81,22 @@ type ReqBody struct {
 	Year      int     `json:"year"`
 	Threshold float64 `json:"threshold"`
 	Week      string  `json:"week"`
+	Date      string  `json:"date"`
+	StartDay  *int    `json:"start_day"`
+	AcquisitionsUsed int `json:"acquisitions_used"`
+	AcquisitionLimit *d.AcquisitionLimit `json:"acquisition_limit"`
+	RosterTemplate *d.RosterTemplate `json:"roster_template"`
+	PlayProbabilities d.PlayProbabilities `json:"play_probabilities"`
+	Projection string `json:"projection"`
+	Slotting string `json:"slotting"`
+	RecencyWeights *d.RecencyWeighted `json:"recency_weights"`
+	Categories *d.CategoryLeague `json:"categories"`
+	OpponentTeamName string `json:"opponent_team_name"`
+	PointsSoFar float64 `json:"points_so_far"`
+	OpponentPointsSoFar float64 `json:"opponent_points_so_far"`
+	Optimizer *OptimizerOptions `json:"optimizer"`
+	Seed      *int64  `json:"seed"`
+	Refresh   bool    `json:"force_refresh"`
 }
 
 // Slimmed version of a player for the response
@@ -95,5 +120,10 @@ type Response struct {
 	Improvement int
 	Timestamp 	string
 	Week 				string
+	StartDay    int
 	Threshold		float64
+	Seed 				int64
+	EarlyTerminated bool
+	WinProbability *float64
+	OptimalityGap *float64
 }
\ No newline at end of file
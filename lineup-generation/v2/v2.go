@@ -2,19 +2,110 @@ package main
 
 import (
 	"fmt"
+	"context"
+	"os"
 	"time"
-	"sync"
+	"math/rand"
 	"net/http"
 	"encoding/json"
+	_ "time/tzdata"
 
+	"v2/jobs"
+	"v2/cache"
 	t "v2/team"
 	d "v2/data"
 	u "v2/utils"
 	p "v2/population"
 )
 
+// Limits for the cache of optimizer responses
+const (
+	CacheSize = 256
+	CacheTTL  = 15 * time.Minute
+)
+
+var ResultCache = cache.New(CacheSize, CacheTTL)
+
+// Directory holding a schedule file for every season the server can optimize for
+const ScheduleDir = "./static"
+
+var Schedules = d.NewScheduleRegistry()
+
+// Schedule dates follow the NBA, so "today" is worked out on US Eastern time
+var ScheduleLocation = LoadScheduleLocation()
+
+// Function to load US Eastern time, falling back to UTC if the zone database is unavailable
+func LoadScheduleLocation() *time.Location {
+	location, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		fmt.Println("Error loading schedule time zone:", err)
+		return time.UTC
+	}
+	return location
+}
+
+// Where players come from. Setting CV_ROSTER_FILE and CV_FREE_AGENTS_FILE serves them from local JSON files for offline development
+var Players d.PlayerProvider = NewPlayerProvider()
+
+// Function to pick the player provider based on the environment
+func NewPlayerProvider() d.PlayerProvider {
+	roster_path, free_agents_path := os.Getenv("CV_ROSTER_FILE"), os.Getenv("CV_FREE_AGENTS_FILE")
+	if roster_path != "" && free_agents_path != "" {
+		fmt.Println("Serving players from", roster_path, "and", free_agents_path)
+		return d.FileProvider{RosterPath: roster_path, FreeAgentsPath: free_agents_path}
+	}
+	return d.BackendProvider{}
+}
+
+// Projections from an outside source, served when a request asks for them. Setting CV_PROJECTIONS_FILE loads them at startup
+var FileProjections = LoadFileProjections()
+
+// Function to load the outside projections if there are any
+func LoadFileProjections() *d.FileProjector {
+	path := os.Getenv("CV_PROJECTIONS_FILE")
+	if path == "" {
+		return nil
+	}
+	projector, err := d.LoadFileProjector(path)
+	if err != nil {
+		fmt.Println("Error loading projections:", err)
+		return nil
+	}
+	fmt.Println("Serving projections from", path)
+	return projector
+}
+
+// Function to pick how players are projected for a request, defaulting to the season average
+func ResolveProjector(req u.ReqBody) (d.Projector, error) {
+	switch req.Projection {
+	case "", d.ProjectionSeason:
+		return d.SeasonAverage{}, nil
+	case d.ProjectionRecent:
+		weights := d.DefaultRecencyWeighted()
+		if req.RecencyWeights != nil {
+			weights = *req.RecencyWeights
+		}
+		if err := weights.Validate(); err != nil {
+			return nil, err
+		}
+		return weights, nil
+	case d.ProjectionFile:
+		if FileProjections == nil {
+			return nil, fmt.Errorf("%w: no projections file is loaded", d.ErrInvalidProjection)
+		}
+		return FileProjections, nil
+	}
+	return nil, fmt.Errorf("%w: unknown projection %q", d.ErrInvalidProjection, req.Projection)
+}
+
 func main() {
 
+	// Load the schedules up front so that a missing or broken file stops the server from starting
+	if err := Schedules.LoadDir(ScheduleDir); err != nil {
+		panic(err)
+	}
+	fmt.Println("Loaded schedules for seasons", Schedules.Years())
+
 	fmt.Println("Server started on port 8080")
 
 	// Handle request
@@ -34,17 +125,21 @@ func main() {
 		err := json.NewDecoder(r.Body).Decode(&request)
 		if err != nil {
 			fmt.Println(err)
-			http.Error(w, "Failed to decode request body", http.StatusBadRequest)
+			WriteBadRequest(w, "Failed to decode request body")
 			return
 		}
 
 		// Print the decoded request for debugging purposes
 		fmt.Printf("Received request: %+v\n", request)
 
-		// Check cache to see if the request has already been made
+		response, err := OptimizeStreaming(r.Context(), request, nil)
+		if err != nil {
+			WriteError(w, err)
+			return
+		}
 
 		// Respond with a JSON-encoded message
-		json_data, err := json.Marshal(OptimizeStreaming(request))
+		json_data, err := json.Marshal(response)
 		if err != nil {
 			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
 			return
@@ -57,6 +152,15 @@ func main() {
 		}
 	})
 
+	// Handle requests that stream their progress
+	RegisterStreamRoutes()
+
+	// Handle asynchronous optimization jobs
+	job_manager := jobs.NewManager(JobWorkers, JobQueueSize, JobTTL, func(ctx context.Context, req u.ReqBody, on_progress p.ProgressFunc) (u.Response, error) {
+		return OptimizeStreaming(ctx, req, on_progress)
+	})
+	RegisterJobRoutes(job_manager)
+
 	// Start server
 	if err := http.ListenAndServe(":8080", nil); err != nil {
 		panic(err)
@@ -64,73 +168,192 @@ func main() {
 
 }
 
-func OptimizeStreaming(req u.ReqBody) u.Response {
+// Function to run the optimizer for a request. Cancelling ctx aborts the run, while running out of the optimizer's time budget returns the best lineup found so far
+func OptimizeStreaming(ctx context.Context, req u.ReqBody, on_progress p.ProgressFunc) (u.Response, error) {
 	start := time.Now()
-	d.InitSchedule("./static/schedule24-25.json")
+
+	// Resolve the tuning knobs for the genetic algorithm
+	config, err := req.Optimizer.Resolve()
+	if err != nil {
+		return u.Response{}, err
+	}
+
+	// Look up the schedule for the season being played and the week the request is for, defaulting to the current week
+	schedule, err := Schedules.Season(req.Year)
+	if err != nil {
+		return u.Response{}, err
+	}
+	week, day, err := schedule.ResolveWeek(req.Week, req.Date, time.Now().In(ScheduleLocation))
+	if err != nil {
+		return u.Response{}, err
+	}
+
+	// Pick up from the day the request was made on unless told otherwise, since the days before it have already been played
+	start_day := day
+	if req.StartDay != nil {
+		start_day = *req.StartDay
+	}
+	if req.AcquisitionsUsed < 0 {
+		return u.Response{}, fmt.Errorf("%w: %d", d.ErrInvalidAcquisitions, req.AcquisitionsUsed)
+	}
+	req.Week, req.StartDay = week, &start_day
+
+	// Use the league's acquisition limit if it has one
+	acquisition_limit := d.DefaultAcquisitionLimit(schedule.GetGameSpan(week))
+	if req.AcquisitionLimit != nil {
+		acquisition_limit = *req.AcquisitionLimit
+	}
+	if err := acquisition_limit.Validate(); err != nil {
+		return u.Response{}, err
+	}
+	req.AcquisitionLimit = &acquisition_limit
+
+	// Use the league's roster layout if it has one
+	if req.RosterTemplate != nil {
+		if err := req.RosterTemplate.Validate(); err != nil {
+			return u.Response{}, err
+		}
+	}
+
+	// Use the request's chances of playing for each injury designation in place of the defaults
+	if err := req.PlayProbabilities.Validate(); err != nil {
+		return u.Response{}, err
+	}
+	req.PlayProbabilities = req.PlayProbabilities.Merge()
+
+	// Project each game the way the request asks
+	projector, err := ResolveProjector(req)
+	if err != nil {
+		return u.Response{}, err
+	}
+
+	// Choose who starts the way the request asks
+	if err := d.ValidateSlotting(req.Slotting); err != nil {
+		return u.Response{}, err
+	}
+
+	// Playing for the win only makes sense against someone
+	if config.Fitness == u.FitnessWinProbability && req.OpponentTeamName == "" {
+		return u.Response{}, fmt.Errorf("%w: optimizer.fitness %q needs opponent_team_name", u.ErrInvalidOptimizer, u.FitnessWinProbability)
+	}
+
+	// Category leagues are scored on categories rather than points, which the exact solver doesn't know how to maximize
+	if req.Categories != nil && config.Mode == u.ModeExact {
+		return u.Response{}, fmt.Errorf("%w: optimizer.mode %q doesn't support category leagues", u.ErrInvalidOptimizer, u.ModeExact)
+	}
+	if req.Categories != nil {
+		categories := req.Categories.WithDefaults()
+		if err := categories.Validate(); err != nil {
+			return u.Response{}, err
+		}
+		req.Categories = &categories
+	}
 
 	// League information
-	league_id := req.LeagueId
-	espn_s2 := req.EspnS2
-	swid := req.Swid
-	team_name := req.TeamName
-	year := req.Year
-	week := req.Week
-
-	fa_count := 100
+	league := d.LeagueInfo{LeagueId: req.LeagueId, EspnS2: req.EspnS2, Swid: req.Swid, TeamName: req.TeamName, Year: req.Year}
+
+	fa_count := config.FaCount
 	threshold := req.Threshold
 
+	// Fetch our players first so that a request that has already been made against the same players is answered without slotting anyone
+	roster_map, free_agents, err := t.FetchPlayers(ctx, Players, schedule, league, fa_count, week, start_day)
+	if err != nil {
+		return u.Response{}, err
+	}
+	cache_key := cache.Key(req, config, roster_map, free_agents)
+	if cached, ok := ResultCache.Get(cache_key); ok && !req.Refresh {
+		fmt.Println("Returning cached response from", cached.Timestamp)
+		return cached, nil
+	}
+
 	// Initialize the BaseTeam object
-	bt := t.InitBaseTeam(league_id, espn_s2, swid, team_name, year, fa_count, week, threshold)
-
-	// Create new populations
-	ev1 := p.InitPopulation(bt, 20)
-	ev2 := p.InitPopulation(bt, 20)
-
-	// Evolve the populations concurrently
-	var wg sync.WaitGroup
-	wg.Add(2)
-	go func() {
-		defer wg.Done()
-		for i := 0; i < 10; i++ {
-			ev1.Evolve(bt)
-		}
-	}()
-	go func() {
-		defer wg.Done()
-		for i := 0; i < 10; i++ {
-			ev2.Evolve(bt)
+	options := t.BaseTeamOptions{Template: req.RosterTemplate, PlayProbabilities: req.PlayProbabilities, Projector: projector, Slotting: req.Slotting, AcquisitionLimit: acquisition_limit, AcquisitionsUsed: req.AcquisitionsUsed}
+	bt := t.BuildBaseTeam(schedule, options, roster_map, free_agents, week, start_day, threshold)
+
+	// Project the opponent's week the same way as ours if the request names them
+	var opponent *t.BaseTeam
+	if req.OpponentTeamName != "" {
+		opponent_league := league
+		opponent_league.TeamName = req.OpponentTeamName
+		opponent, err = t.InitOpponent(ctx, Players, schedule, options, opponent_league, week, start_day)
+		if err != nil {
+			return u.Response{}, err
 		}
-	}()
-	wg.Wait()
-	
-	// Combine the populations
-	ev1.Population = append(ev1.Population, ev2.Population...)
-	ev1.NumChromosomes = len(ev1.Population)
-	fmt.Println("Combined population size: ", ev1.NumChromosomes)
-	
-	// Evolve the combined population
-	for i := 0; i < 10; i++ {
-		ev1.Evolve(bt)
 	}
 
-	ev1.SortByFitness()
-	best_chromosome_index := ev1.NumChromosomes - 1
-	for ev1.Population[best_chromosome_index].TotalAcquisitions > d.ScheduleMap.GetGameSpan(week) + 1 {
-		best_chromosome_index--
+	// Seed the random number generator that every population derives its randomness from, so that a run can be replayed
+	seed := time.Now().UnixNano()
+	if req.Seed != nil {
+		seed = *req.Seed
+	}
+	rng := rand.New(rand.NewSource(seed))
+
+	// The time budget only applies to the genetic algorithm itself
+	run_ctx := ctx
+	if config.TimeBudgetMs > 0 {
+		var cancel context.CancelFunc
+		run_ctx, cancel = context.WithTimeout(ctx, time.Duration(config.TimeBudgetMs) * time.Millisecond)
+		defer cancel()
+	}
+
+	// Score chromosomes on categories or the chance of winning the matchup if the request asks for it
+	var matchup *p.WinProbabilityFitness
+	if opponent != nil {
+		matchup = p.NewWinProbabilityFitness(bt, opponent, req.PointsSoFar, req.OpponentPointsSoFar)
+	}
+	fitness := p.NewFitnessFunc(config)
+	if req.Categories != nil {
+		fitness = p.NewCategoryFitness(bt, *req.Categories)
+	} else if config.Fitness == u.FitnessWinProbability {
+		fitness = matchup
 	}
-	best_chromosome := ev1.Population[best_chromosome_index]
-	best_chromosome.AddBackNonStreamablePlayers(bt)
 
+	// Search for the best lineup with the genetic algorithm, or outright with the exact solver
+	var best_chromosome *p.Chromosome
+	var optimality_gap *float64
+	early_terminated := false
+	if config.Mode == u.ModeExact {
+		result := p.Solve(run_ctx, bt, p.SolverOptions{MaxNodes: config.SolverNodes})
+		fmt.Println("Exact solver searched", result.Nodes, "nodes, gap", result.Gap())
+		gap := result.Gap()
+		best_chromosome, optimality_gap, early_terminated = result.Chromosome, &gap, result.Stopped
+	} else {
+		best_chromosome, early_terminated = RunGenetic(run_ctx, bt, config, rng, fitness, on_progress)
+	}
+
+	// If the caller went away there is nobody to return a lineup to, but if the time budget ran out we return what we have
+	if ctx.Err() != nil {
+		return u.Response{}, ctx.Err()
+	}
+
+	// Score the lineup found the same way as the lineup of making no moves
+	if best_chromosome != nil {
+		best_chromosome.FitnessScore = fitness.Score(best_chromosome)
+	}
 
 	// Get the initial fitness score
 	base_chromosome := p.InitChromosome(bt)
 	for _, gene := range base_chromosome.Genes {
 		gene.InsertStreamablePlayers(bt)
 	}
-	base_chromosome.ScoreFitness()
+	base_chromosome.FitnessScore = fitness.Score(base_chromosome)
+
+	// Fall back to making no moves at all if nothing stays within the acquisitions left for the week
+	if best_chromosome == nil {
+		best_chromosome = base_chromosome
+	}
+
+	// The improvement is always in points, whatever the chromosomes were scored on, so it has to be worked out before the rest of the roster is added back
+	improvement := int(best_chromosome.Points() - base_chromosome.Points())
+	var win_probability *float64
+	if matchup != nil {
+		probability := matchup.WinProbability(best_chromosome)
+		win_probability = &probability
+	}
+	best_chromosome.AddBackNonStreamablePlayers(bt)
 
 	// Print the best chromosome
-	fmt.Println(bt.Score + best_chromosome.FitnessScore, "vs", bt.Score + base_chromosome.FitnessScore, "diff", best_chromosome.FitnessScore - base_chromosome.FitnessScore)
+	fmt.Println(best_chromosome.FitnessScore, "vs", base_chromosome.FitnessScore, "diff", improvement, "points")
 	best_chromosome.Print()
 	elapsed := time.Since(start)
 	fmt.Println("Time to run algorithm: ", elapsed)
@@ -138,6 +361,40 @@ func OptimizeStreaming(req u.ReqBody) u.Response {
 	current_time := time.Now()
 	layout := "1/2/2006 3:04PM"
 
-	return u.Response{Lineup: best_chromosome.Slim(), Improvement: best_chromosome.FitnessScore - base_chromosome.FitnessScore, Timestamp: current_time.Format(layout), Week: week, Threshold: threshold}
+	response := u.Response{Lineup: best_chromosome.Slim(), Improvement: improvement, Timestamp: current_time.Format(layout), Week: week, StartDay: start_day, Threshold: threshold, Seed: seed, EarlyTerminated: early_terminated, WinProbability: win_probability, OptimalityGap: optimality_gap}
+
+	// Only cache complete runs so that a tight time budget doesn't stick around for everyone else
+	if !early_terminated {
+		ResultCache.Set(cache_key, response)
+	}
+
+	return response, nil
 
-}
\ No newline at end of file
+}
+
+// Function to evolve a population of lineups with the genetic algorithm and pick the fittest one that stays within the acquisitions left
+// for the week, or nil if there is none. Running out of time returns the best lineup so far and reports that the run was cut short
+func RunGenetic(run_ctx context.Context, bt *t.BaseTeam, config u.OptimizerConfig, rng *rand.Rand, fitness p.FitnessFunc, on_progress p.ProgressFunc) (*p.Chromosome, bool) {
+
+	// Create the islands and evolve them concurrently, migrating their best chromosomes between them
+	archipelago, err := p.InitArchipelago(run_ctx, bt, config, rng)
+	archipelago.SetProgressFunc(on_progress)
+	archipelago.SetFitness(fitness)
+	if err == nil {
+		err = archipelago.Evolve(run_ctx, bt, config.IslandGenerations)
+	}
+
+	// Combine the populations
+	ev := archipelago.Merge()
+	fmt.Println("Combined population size: ", ev.NumChromosomes)
+
+	// Evolve the combined population
+	for i := 0; i < config.CombinedGenerations && err == nil; i++ {
+		err = ev.Evolve(run_ctx, bt)
+	}
+	if err != nil {
+		fmt.Println("Time budget exhausted after generation", ev.Generation)
+	}
+
+	return ev.BestValid(), err != nil
+}